@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+// configStatusAgeing turns on counting the tickets left too long in a
+// status, which are saved to Worksheet. Each rule is counted on every board.
+type configStatusAgeing struct {
+	Worksheet string              `json:"worksheet"`
+	Rules     []configStaleStatus `json:"rules"`
+}
+
+// configStaleStatus counts the open tickets that have been in Status for
+// more than Days. With WithoutMemberNote, tickets with a member note in
+// that time are not counted.
+type configStaleStatus struct {
+	Name              string `json:"name"`
+	Status            string `json:"status"`
+	Days              int    `json:"days"`
+	WithoutMemberNote bool   `json:"without_member_note"`
+}
+
+func (a configStatusAgeing) enabled() bool {
+	return a.Worksheet != "" && len(a.Rules) > 0
+}
+
+func (a configStatusAgeing) validate() error {
+	for _, r := range a.Rules {
+		if r.Name == "" || r.Status == "" || r.Days <= 0 {
+			return fmt.Errorf("each status_ageing rule needs a name, status and days")
+		}
+	}
+	return nil
+}
+
+func (a configStatusAgeing) rule(name string) (configStaleStatus, bool) {
+	for _, r := range a.Rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return configStaleStatus{}, false
+}
+
+// staleFields are the ticket fields needed to list stale tickets
+var staleFields = append([]string{"status"}, psa.TicketSummaryFields...)
+
+// staleTicket is a ticket and how long it has been in its current status
+type staleTicket struct {
+	psa.Ticket
+	since time.Time
+}
+
+func (t staleTicket) daysInStatus() int {
+	return int(clock().Sub(t.since).Hours() / 24)
+}
+
+// findStaleTickets returns the open tickets on the board breaking the rule.
+// The time in status comes from the audit trail, as _info/lastUpdated
+// changes whenever a note is added, automated or not.
+func findStaleTickets(ctx context.Context, client *psa.Client, board configBoards, rule configStaleStatus) ([]staleTicket, error) {
+	tickets, err := client.GetOpenTicketsByBoardIDInStatusContext(ctx, board.ID, rule.Status, staleFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := clock().AddDate(0, 0, -rule.Days)
+	stale := []staleTicket{}
+	for _, t := range tickets {
+		audit, err := client.GetTicketAuditTrailContext(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		since := t.StatusSince(audit)
+		if since.After(cutoff) {
+			continue
+		}
+
+		if rule.WithoutMemberNote {
+			noted, err := hasMemberNoteSince(ctx, client, t.ID, cutoff)
+			if err != nil {
+				return nil, err
+			}
+			if noted {
+				continue
+			}
+		}
+		stale = append(stale, staleTicket{t, since})
+	}
+	return stale, nil
+}
+
+func hasMemberNoteSince(ctx context.Context, client *psa.Client, ticketID int, since time.Time) (bool, error) {
+	notes, err := client.GetTicketNotesContext(ctx, ticketID)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range notes {
+		if n.IsMemberResponse() && n.DateCreated.After(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// statusAgeingStats holds the stale tickets for each rule in config order,
+// keyed by board name
+type statusAgeingStats map[string][]int
+
+// collectStatusAgeingStats counts the tickets breaking each rule on every
+// board. It returns nil when the rules are not turned on.
+func collectStatusAgeingStats(ctx context.Context, c config) (statusAgeingStats, error) {
+	if !c.StatusAgeing.enabled() {
+		return nil, nil
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := statusAgeingStats{}
+	for _, board := range c.Boards {
+		for _, rule := range c.StatusAgeing.Rules {
+			stale, err := findStaleTickets(ctx, client, board, rule)
+			if err != nil {
+				return nil, fmt.Errorf("board %s: %s", board.Name, err)
+			}
+			stats[board.Name] = append(stats[board.Name], len(stale))
+		}
+	}
+	return stats, nil
+}
+
+func printStatusAgeingStats(c config, stats statusAgeingStats) {
+	if len(stats) == 0 {
+		return
+	}
+	for _, board := range c.Boards {
+		fmt.Printf("%s by status\n", board.Name)
+		for i, rule := range c.StatusAgeing.Rules {
+			fmt.Printf("  %-30.30s: %3d\n", rule.Name, stats[board.Name][i])
+		}
+		fmt.Println("---------------------------")
+	}
+}
+
+var statusAgeingHeadings = []string{"Date", "Board", "Rule", "Tickets"}
+
+// writeStatusAgeingStats adds a row per board and rule to the status ageing
+// worksheet, replacing any rows already saved today. The worksheet is added
+// if it does not exist.
+func writeStatusAgeingStats(f *xlsx.File, c config, stats statusAgeingStats) error {
+	if !c.StatusAgeing.enabled() {
+		return nil
+	}
+
+	sheet, err := getOrAddSheet(f, c.StatusAgeing.Worksheet, statusAgeingHeadings)
+	if err != nil {
+		return err
+	}
+	today := clock().UTC().Truncate(24 * time.Hour)
+	if err := removeRowsForDate(sheet, today); err != nil {
+		return err
+	}
+
+	for _, board := range c.Boards {
+		for i, rule := range c.StatusAgeing.Rules {
+			row := sheet.AddRow()
+			row.AddCell().SetValue(today)
+			row.AddCell().SetString(board.Name)
+			row.AddCell().SetString(rule.Name)
+			row.AddCell().SetInt(stats[board.Name][i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// defaultClosedStatuses are the ConnectWise status names that close a ticket
+var defaultClosedStatuses = []string{">Closed", "Closed"}
+
+func (c config) closedStatuses() []string {
+	if len(c.ClosedStatuses) == 0 {
+		return defaultClosedStatuses
+	}
+	return c.ClosedStatuses
+}
+
+// closedFields are the ticket fields the resolution stats need
+var closedFields = []string{"id", "dateEntered", "closedDate"}
+
+// collectClosedStats works out, for tickets closed in the last week:
+//
+// closed, how many there were
+//
+// resolutionMedianHours and resolutionP90Hours, the median and 90th
+// percentile of the time from being entered to being closed
+//
+// reopened, how many tickets updated in the last week had their status
+// changed from a closed status in that time, and reopenRate, that as a
+// percentage of the tickets closed
+func collectClosedStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	stats := extraStats{}
+
+	closed := 0
+	if board.hasExtra("resolutionMedianHours") || board.hasExtra("resolutionP90Hours") {
+		tickets, err := client.GetClosedTicketsByBoardIDContext(ctx, board.ID, 7, closedFields...)
+		if err != nil {
+			return nil, err
+		}
+		hours := make([]float64, 0, len(tickets))
+		for _, t := range tickets {
+			hours = append(hours, t.ClosedDate.Sub(t.DateEntered).Hours())
+		}
+		if v, ok := percentile(hours, 50); ok {
+			stats["resolutionMedianHours"] = v
+		}
+		if v, ok := percentile(hours, 90); ok {
+			stats["resolutionP90Hours"] = v
+		}
+		closed = len(tickets)
+	} else if board.hasExtra("closed") || board.hasExtra("reopenRate") {
+		n, err := client.CountClosedTicketsByBoardIDContext(ctx, board.ID, 7)
+		if err != nil {
+			return nil, err
+		}
+		closed = n
+	}
+	if board.hasExtra("closed") {
+		stats["closed"] = float64(closed)
+	}
+
+	if board.hasExtra("reopened") || board.hasExtra("reopenRate") {
+		tickets, err := client.GetTicketsByBoardIDUpdatedInContext(ctx, board.ID, 7, psa.TicketIDFields...)
+		if err != nil {
+			return nil, err
+		}
+		since := clock().AddDate(0, 0, -7)
+		reopened := 0
+		for _, t := range tickets {
+			audit, err := client.GetTicketAuditTrailContext(ctx, t.ID)
+			if err != nil {
+				return nil, err
+			}
+			if wasReopened(audit, c.closedStatuses(), since) {
+				reopened++
+			}
+		}
+		if board.hasExtra("reopened") {
+			stats["reopened"] = float64(reopened)
+		}
+		if board.hasExtra("reopenRate") && closed > 0 {
+			stats["reopenRate"] = float64(reopened) / float64(closed) * 100
+		}
+	}
+
+	return stats, nil
+}
+
+// wasReopened reports whether the audit trail has a change from a closed
+// status to an open one since the given time
+func wasReopened(audit []psa.Audit, closedStatuses []string, since time.Time) bool {
+	for _, a := range audit {
+		if a.EnteredDate.Before(since) {
+			continue
+		}
+		from, to, ok := a.StatusChange()
+		if ok && containsString(closedStatuses, from) && !containsString(closedStatuses, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// percentile returns the pth percentile of values by the nearest rank
+// method, false if there are none
+func percentile(values []float64, p float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1], true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+const defaultTopCompanies = 10
+
+// configCompanies turns on the per company breakdown, which is saved to
+// Worksheet. Groups map several ConnectWise companies to one customer.
+type configCompanies struct {
+	Worksheet string               `json:"worksheet"`
+	Top       int                  `json:"top"`
+	Groups    []configCompanyGroup `json:"groups"`
+}
+
+// configCompanyGroup is a customer made up of several ConnectWise companies,
+// listed by identifier or name
+type configCompanyGroup struct {
+	Name      string   `json:"name"`
+	Companies []string `json:"companies"`
+}
+
+func (c configCompanies) enabled() bool {
+	return c.Worksheet != ""
+}
+
+func (c configCompanies) top() int {
+	if c.Top <= 0 {
+		return defaultTopCompanies
+	}
+	return c.Top
+}
+
+// customer returns the group the company belongs to, or the company's own
+// name if it is not in one
+func (c configCompanies) customer(company psa.Company) string {
+	for _, g := range c.Groups {
+		for _, name := range g.Companies {
+			if strings.EqualFold(name, company.SiteCode) || strings.EqualFold(name, company.Name) {
+				return g.Name
+			}
+		}
+	}
+	return company.Name
+}
+
+// companyFields are the ticket fields the company breakdown needs
+var companyFields = []string{"id", "company/id", "company/identifier", "company/name"}
+
+// companyStat is the tickets on the configured boards for one customer
+type companyStat struct {
+	Customer string
+	Open     int
+	New      int
+	Closed   int
+}
+
+// companyStats is sorted by new tickets, most first
+type companyStats []companyStat
+
+// collectCompanyStats counts the open tickets, and those entered and closed
+// in the last week, for each customer across the configured boards. It
+// returns nil when the breakdown is not turned on.
+func collectCompanyStats(ctx context.Context, c config) (companyStats, error) {
+	if !c.Companies.enabled() {
+		return nil, nil
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+
+	byCustomer := map[string]*companyStat{}
+	add := func(tickets []psa.Ticket, count func(s *companyStat)) {
+		for _, t := range tickets {
+			name := c.Companies.customer(t.Company)
+			s, ok := byCustomer[name]
+			if !ok {
+				s = &companyStat{Customer: name}
+				byCustomer[name] = s
+			}
+			count(s)
+		}
+	}
+
+	for _, board := range c.Boards {
+		open, err := client.GetOpenTicketsByBoardIDContext(ctx, board.ID, companyFields...)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		add(open, func(s *companyStat) { s.Open++ })
+
+		created, err := client.GetNewTicketsByBoardIDContext(ctx, board.ID, 7, companyFields...)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		add(created, func(s *companyStat) { s.New++ })
+
+		closed, err := client.GetClosedTicketsByBoardIDContext(ctx, board.ID, 7, companyFields...)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		add(closed, func(s *companyStat) { s.Closed++ })
+	}
+
+	stats := make(companyStats, 0, len(byCustomer))
+	for _, s := range byCustomer {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].New != stats[j].New {
+			return stats[i].New > stats[j].New
+		}
+		return stats[i].Customer < stats[j].Customer
+	})
+	return stats, nil
+}
+
+func printTopCompanies(c config, stats companyStats) {
+	if len(stats) == 0 {
+		return
+	}
+	top := stats
+	if len(top) > c.Companies.top() {
+		top = top[:c.Companies.top()]
+	}
+
+	fmt.Printf("Top %d companies by new tickets\n", len(top))
+	for _, s := range top {
+		fmt.Printf("  %-30.30s: %3d new %3d open %3d closed\n", s.Customer, s.New, s.Open, s.Closed)
+	}
+	fmt.Println("---------------------------")
+}
+
+var companyHeadings = []string{"Date", "Customer", "Open", "New", "Closed"}
+
+// writeCompanyStats adds a row per customer to the companies worksheet,
+// replacing any rows already saved today. The worksheet is added if it
+// does not exist.
+func writeCompanyStats(f *xlsx.File, c config, stats companyStats) error {
+	if !c.Companies.enabled() {
+		return nil
+	}
+
+	sheet, err := getOrAddSheet(f, c.Companies.Worksheet, companyHeadings)
+	if err != nil {
+		return err
+	}
+	today := clock().UTC().Truncate(24 * time.Hour)
+	if err := removeRowsForDate(sheet, today); err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		row := sheet.AddRow()
+		row.AddCell().SetValue(today)
+		row.AddCell().SetString(s.Customer)
+		row.AddCell().SetInt(s.Open)
+		row.AddCell().SetInt(s.New)
+		row.AddCell().SetInt(s.Closed)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDashAddr  = ":8080"
+	defaultDashWeeks = 12
+
+	// dashboardShutdownTimeout is how long requests in flight are given to
+	// finish once the dashboard is stopped
+	dashboardShutdownTimeout = 5 * time.Second
+)
+
+type configDash struct {
+	Addr  string `json:"addr"`
+	Weeks int    `json:"weeks"`
+}
+
+func (d configDash) addr() string {
+	if d.Addr == "" {
+		return defaultDashAddr
+	}
+	return d.Addr
+}
+
+func (d configDash) weeks() int {
+	if d.Weeks <= 0 {
+		return defaultDashWeeks
+	}
+	return d.Weeks
+}
+
+// configGoal sets the colour thresholds for a metric. When Green is below
+// Red lower values are better, otherwise higher values are better, so
+// {"green": 5, "red": 10} is green at 5 or under and red at 10 or over.
+type configGoal struct {
+	Green int `json:"green"`
+	Red   int `json:"red"`
+}
+
+// status returns green, amber or red for v
+func (g configGoal) status(v int) string {
+	if g.Green <= g.Red {
+		switch {
+		case v <= g.Green:
+			return "green"
+		case v >= g.Red:
+			return "red"
+		}
+		return "amber"
+	}
+	switch {
+	case v >= g.Green:
+		return "green"
+	case v <= g.Red:
+		return "red"
+	}
+	return "amber"
+}
+
+// runDashboard serves the scorecard page and JSON endpoints. The workbook is
+// re-read on each request so the page always shows the last saved run. When
+// a metrics interval is configured the live stats are also served on
+// /metrics for Prometheus. The server shuts down when ctx is cancelled.
+func runDashboard(ctx context.Context, c config) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", dashboardPage(c))
+	mux.HandleFunc("/api/latest", dashboardLatest(c))
+	mux.HandleFunc("/api/history", dashboardHistory(c))
+
+	interval, err := c.Metrics.interval()
+	if err != nil {
+		return err
+	}
+	if interval > 0 {
+		metrics := newMetricsRegistry()
+		mux.Handle("/metrics", metrics)
+		go metrics.run(ctx, c, interval)
+	}
+
+	srv := &http.Server{Addr: c.Dashboard.addr(), Handler: mux}
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		logger.Info("dashboard shutting down")
+		sctx, cancel := context.WithTimeout(context.Background(), dashboardShutdownTimeout)
+		defer cancel()
+		shutdown <- srv.Shutdown(sctx)
+	}()
+
+	logger.Info("dashboard listening", "addr", c.Dashboard.addr())
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return <-shutdown
+}
+
+type latestBoard struct {
+	Board string         `json:"board"`
+	Date  time.Time      `json:"date"`
+	Stats map[string]int `json:"stats"`
+}
+
+func dashboardLatest(c config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		history, err := readHistory(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		latest := []latestBoard{}
+		for _, h := range history {
+			if s, ok := h.latest(); ok {
+				latest = append(latest, latestBoard{h.Board, s.Date, s.Stats})
+			}
+		}
+		writeJSON(w, latest)
+	}
+}
+
+// dashboardHistory returns every snapshot, optionally for ?board=Name only
+func dashboardHistory(c config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		history, err := readHistory(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		board := r.URL.Query().Get("board")
+		if board == "" {
+			writeJSON(w, history)
+			return
+		}
+		for _, h := range history {
+			if h.Board == board {
+				writeJSON(w, h)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type dashCell struct {
+	Value     int
+	Status    string
+	Sparkline template.HTML
+}
+
+type dashRow struct {
+	Board string
+	Date  time.Time
+	Cells []dashCell
+}
+
+type dashPage struct {
+	Metrics []boardMetric
+	Rows    []dashRow
+	Updated time.Time
+}
+
+func dashboardPage(c config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		history, err := readHistory(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page := dashPage{Metrics: boardMetrics, Updated: time.Now()}
+		for i, h := range history {
+			page.Rows = append(page.Rows, buildDashRow(c.Boards[i], h, c.Dashboard.weeks()))
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashTemplate.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func buildDashRow(board configBoards, h boardHistory, weeks int) dashRow {
+	row := dashRow{Board: h.Board}
+	latest, ok := h.latest()
+	if !ok {
+		return row
+	}
+	row.Date = latest.Date
+
+	recent := h.Snapshots
+	if len(recent) > weeks {
+		recent = recent[len(recent)-weeks:]
+	}
+
+	for _, m := range boardMetrics {
+		cell := dashCell{Value: latest.Stats[m.Key]}
+		if goal, ok := board.Goals[m.Key]; ok {
+			cell.Status = goal.status(cell.Value)
+		}
+		values := make([]int, len(recent))
+		for i, s := range recent {
+			values[i] = s.Stats[m.Key]
+		}
+		cell.Sparkline = sparkline(values, 80, 20)
+		row.Cells = append(row.Cells, cell)
+	}
+	return row
+}
+
+// sparkline renders values as an inline SVG line
+func sparkline(values []int, width, height int) template.HTML {
+	if len(values) < 2 {
+		return ""
+	}
+
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	span := max - min
+	if span == 0 {
+		span = 1
+	}
+
+	points := make([]string, len(values))
+	step := float64(width-2) / float64(len(values)-1)
+	for i, v := range values {
+		x := 1 + float64(i)*step
+		y := 1 + float64(height-2)*(1-float64(v-min)/float64(span))
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	return template.HTML(fmt.Sprintf(
+		`<svg class="spark" width="%d" height="%d"><polyline points="%s"/></svg>`,
+		width, height, strings.Join(points, " ")))
+}
+
+var dashTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="300">
+<title>Onebyte Scorecard</title>
+<style>
+body { font-family: sans-serif; margin: 2em; background: #fafafa; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.5em 1em; text-align: center; }
+th.board { text-align: left; }
+td .value { font-size: 1.6em; font-weight: bold; display: block; }
+td.green { background: #c6efce; }
+td.amber { background: #ffeb9c; }
+td.red { background: #ffc7ce; }
+svg.spark polyline { fill: none; stroke: #555; stroke-width: 1.5; }
+.date, .updated { color: #777; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>Onebyte Scorecard</h1>
+<table>
+<tr><th></th>{{range .Metrics}}<th>{{.Label}}</th>{{end}}</tr>
+{{range .Rows}}<tr>
+<th class="board">{{.Board}}{{if not .Date.IsZero}}<br><span class="date">{{.Date.Format "02 Jan 2006"}}</span>{{end}}</th>
+{{range .Cells}}<td class="{{.Status}}"><span class="value">{{.Value}}</span>{{.Sparkline}}</td>{{end}}
+</tr>
+{{end}}</table>
+<p class="updated">Updated {{.Updated.Format "02 Jan 2006 15:04"}}</p>
+</body>
+</html>
+`))
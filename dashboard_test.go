@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunDashboardStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := config{Dashboard: configDash{Addr: "127.0.0.1:0"}}
+
+	done := make(chan error, 1)
+	go func() { done <- runDashboard(ctx, c) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("got %v, want nil after cancelling", err)
+		}
+	case <-time.After(dashboardShutdownTimeout):
+		t.Fatal("dashboard kept running after ctx was cancelled")
+	}
+}
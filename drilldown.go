@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+var detailHeadings = []string{"Ticket", "Summary", "Company", "Age (days)", "Last Updated", "Assigned"}
+
+// runShow lists the tickets behind one board metric, for example
+// scorecard show -board Reactive -metric older31, or those breaking a
+// status_ageing rule with -stale
+func runShow(ctx context.Context, c config, args []string) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	boardFlag := fs.String("board", "", "Service board name")
+	metricFlag := fs.String("metric", "", "Metric key, e.g. older31")
+	staleFlag := fs.String("stale", "", "Status ageing rule name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	board, ok := findBoard(c, *boardFlag)
+	if !ok {
+		return fmt.Errorf("board %q is not configured", *boardFlag)
+	}
+	if *staleFlag != "" {
+		return showStaleTickets(ctx, c, board, *staleFlag)
+	}
+	query, ok := boardQueries[*metricFlag]
+	if !ok {
+		return fmt.Errorf("unknown metric %q, expected one of %s", *metricFlag, metricKeys())
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return err
+	}
+	tickets, err := query.list(ctx, client, board.ID, ticketSummaryFields)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s - %s: %d tickets\n\n", board.Name, *metricFlag, len(tickets))
+	fmt.Printf("%-8s %4s  %-16s  %-20s  %-12s  %s\n", "Ticket", "Age", "Last Updated", "Company", "Assigned", "Summary")
+	for _, t := range tickets {
+		fmt.Printf("%-8d %4d  %-16s  %-20.20s  %-12.12s  %s\n",
+			t.ID, ticketAgeDays(t), t.Info.LastUpdated.Local().Format("2006-01-02 15:04"),
+			t.Company.Name, t.Resources, t.Summary)
+	}
+	return nil
+}
+
+// showStaleTickets lists the tickets breaking a status ageing rule with how
+// long each has been in its status
+func showStaleTickets(ctx context.Context, c config, board configBoards, name string) error {
+	rule, ok := c.StatusAgeing.rule(name)
+	if !ok {
+		return fmt.Errorf("status ageing rule %q is not configured", name)
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return err
+	}
+	tickets, err := findStaleTickets(ctx, client, board, rule)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s - %s: %d tickets\n\n", board.Name, rule.Name, len(tickets))
+	fmt.Printf("%-8s %9s  %-20s  %-12s  %s\n", "Ticket", "In Status", "Company", "Assigned", "Summary")
+	for _, t := range tickets {
+		fmt.Printf("%-8d %9d  %-20.20s  %-12.12s  %s\n",
+			t.ID, t.daysInStatus(), t.Company.Name, t.Resources, t.Summary)
+	}
+	return nil
+}
+
+func findBoard(c config, name string) (configBoards, bool) {
+	for _, b := range c.Boards {
+		if strings.EqualFold(b.Name, name) {
+			return b, true
+		}
+	}
+	return configBoards{}, false
+}
+
+func metricKeys() string {
+	keys := make([]string, len(boardMetrics))
+	for i, m := range boardMetrics {
+		keys[i] = m.Key
+	}
+	return strings.Join(keys, ", ")
+}
+
+func ticketAgeDays(t psa.Ticket) int {
+	return int(clock().Sub(t.DateEntered).Hours() / 24)
+}
+
+// maxSheetName is Excel's limit on the length of a worksheet name
+const maxSheetName = 31
+
+// detailSheet identifies a board metric saved to its own worksheet
+type detailSheet struct {
+	board  string
+	metric string
+}
+
+// detailSheetNames names the worksheet listing the tickets behind each
+// board's detail metrics. Characters Excel does not allow are replaced with
+// _, names are cut to Excel's limit, and one already taken by a configured
+// worksheet or an earlier detail sheet gets a ~2, ~3, ... suffix. Boards are
+// named in config order so each keeps its worksheet from run to run.
+func detailSheetNames(c config) map[detailSheet]string {
+	taken := map[string]bool{}
+	for _, name := range configWorksheets(c) {
+		taken[strings.ToLower(name)] = true
+	}
+
+	names := map[detailSheet]string{}
+	for _, board := range c.Boards {
+		for _, metric := range board.Details {
+			key := detailSheet{board.Name, metric}
+			if _, ok := names[key]; ok {
+				continue
+			}
+			base := sheetNameEscaper.Replace(board.Worksheet + " " + metric)
+			name := truncateSheetName(base, maxSheetName)
+			for n := 2; taken[strings.ToLower(name)]; n++ {
+				suffix := fmt.Sprintf("~%d", n)
+				name = truncateSheetName(base, maxSheetName-len(suffix)) + suffix
+			}
+			taken[strings.ToLower(name)] = true
+			names[key] = name
+		}
+	}
+	return names
+}
+
+// configWorksheets lists every worksheet named in the config
+func configWorksheets(c config) []string {
+	names := []string{c.Companies.Worksheet, c.Sources.Worksheet, c.Staff.Worksheet, c.StatusAgeing.Worksheet}
+	for _, b := range c.Boards {
+		names = append(names, b.Worksheet)
+	}
+	for _, t := range c.Teams {
+		names = append(names, t.Worksheet)
+	}
+	return names
+}
+
+// sheetNameEscaper replaces the characters Excel does not allow in a
+// worksheet name
+var sheetNameEscaper = strings.NewReplacer(
+	"[", "_", "]", "_", ":", "_", "*", "_", "?", "_", "/", "_", `\`, "_")
+
+// truncateSheetName cuts name to at most max characters, never splitting a
+// multi-byte character
+func truncateSheetName(name string, max int) string {
+	if utf8.RuneCountInString(name) <= max {
+		return name
+	}
+	return string([]rune(name)[:max])
+}
+
+// saveDetailSheet replaces the contents of the metric's detail worksheet,
+// adding the worksheet if it does not exist
+func saveDetailSheet(f *xlsx.File, name string, board configBoards, metric string, tickets []psa.Ticket) error {
+	if _, ok := boardQueries[metric]; !ok {
+		return fmt.Errorf("error: unknown detail metric %v for board %v", metric, board.Name)
+	}
+
+	sheet := getSheet(f, name)
+	if sheet == nil {
+		var err error
+		if sheet, err = f.AddSheet(name); err != nil {
+			return err
+		}
+	}
+	sheet.Rows = nil
+	sheet.MaxRow = 0
+	sheet.MaxCol = 0
+
+	row := sheet.AddRow()
+	for _, h := range detailHeadings {
+		row.AddCell().SetString(h)
+	}
+
+	for _, t := range tickets {
+		row := sheet.AddRow()
+		row.AddCell().SetInt(t.ID)
+		row.AddCell().SetString(t.Summary)
+		row.AddCell().SetString(t.Company.Name)
+		row.AddCell().SetInt(ticketAgeDays(t))
+		row.AddCell().SetDateTime(t.Info.LastUpdated)
+		row.AddCell().SetString(t.Resources)
+	}
+	return nil
+}
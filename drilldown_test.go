@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDetailSheetNames(t *testing.T) {
+	c := config{
+		Boards: []configBoards{
+			{Name: "Reactive", Worksheet: "Reactive", Details: []string{"open", "older31"}},
+			{Name: "Managed Services Reactive", Worksheet: "Managed Services Reactive", Details: []string{"notAssigned", "noUpdate7"}},
+			{Name: "Managed Services Reactive 2", Worksheet: "Managed Services Reactive 2", Details: []string{"notAssigned"}},
+			{Name: "Ünterstützung", Worksheet: "Ünterstützung Büro München Süd", Details: []string{"older7", "older31"}},
+			{Name: "Projects", Worksheet: "Projects", Details: []string{"open", "open"}},
+			{Name: "Sales: Pre/Post [UK]", Worksheet: `Sales: Pre/Post [UK]*?\`, Details: []string{"open"}},
+		},
+		Companies: configCompanies{Worksheet: "projects OPEN"},
+	}
+
+	want := map[detailSheet]string{
+		{"Reactive", "open"}:                           "Reactive open",
+		{"Reactive", "older31"}:                        "Reactive older31",
+		{"Managed Services Reactive", "notAssigned"}:   "Managed Services Reactive notAs",
+		{"Managed Services Reactive", "noUpdate7"}:     "Managed Services Reactive noUpd",
+		{"Managed Services Reactive 2", "notAssigned"}: "Managed Services Reactive 2 not",
+		{"Ünterstützung", "older7"}:                    "Ünterstützung Büro München Süd ",
+		{"Ünterstützung", "older31"}:                   "Ünterstützung Büro München Sü~2",
+		{"Projects", "open"}:                           "Projects open~2",
+		{"Sales: Pre/Post [UK]", "open"}:               "Sales_ Pre_Post _UK____ open",
+	}
+
+	names := detailSheetNames(c)
+	if len(names) != len(want) {
+		t.Errorf("got %d names, want %d", len(names), len(want))
+	}
+	for key, w := range want {
+		got := names[key]
+		if got != w {
+			t.Errorf("%s %s = %q, want %q", key.board, key.metric, got, w)
+		}
+		if !utf8.ValidString(got) || utf8.RuneCountInString(got) > maxSheetName {
+			t.Errorf("%q is not a valid worksheet name", got)
+		}
+	}
+
+	// The names must not change from run to run
+	for i := 0; i < 10; i++ {
+		for key, name := range detailSheetNames(c) {
+			if names[key] != name {
+				t.Fatalf("%s %s was %q, now %q", key.board, key.metric, names[key], name)
+			}
+		}
+	}
+}
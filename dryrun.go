@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+// runDryRun collects the stats, printing each API request as it is made,
+// then applies them to an in-memory copy of the workbook and prints the
+// cells that would change. The workbook is never saved. With printOnly the
+// requests are printed but not sent, and every query returns nothing.
+func runDryRun(ctx context.Context, c config, printOnly bool) error {
+	psaTransport := c.ConnectWise.Transport
+	if psaTransport == nil {
+		psaTransport = psa.NewDefaultTransport(c.ConnectWise)
+	}
+	rmmTransport := c.rmmTransport
+	if rmmTransport == nil {
+		rmmTransport = newRMMTransport(c)
+	}
+	c.ConnectWise.Transport = &printingTransport{"PSA", psaTransport, printOnly}
+	c.rmmTransport = &printingTransport{"RMM", rmmTransport, printOnly}
+
+	stats, err := collectRunStats(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if c.Continuum != "" || printOnly {
+		rmm, err := NewRMMClient(c).GetRMMStats(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nRMM: TSC devices %d, other devices %d\n", rmm.TSCDevices, rmm.OtherDevices)
+	}
+
+	f, err := xlsx.OpenFile(c.StatsFile)
+	if err != nil {
+		return err
+	}
+	before := workbookValues(f)
+	if err := writeRunStats(f, c, stats); err != nil {
+		return err
+	}
+	changes := diffValues(before, workbookValues(f))
+
+	fmt.Printf("\n%d cells would change in %s (not saved)\n", len(changes), c.StatsFile)
+	for _, ch := range changes {
+		fmt.Printf("  %-20s %-6s %q -> %q\n", ch.sheet, ch.cell, ch.old, ch.new)
+	}
+	return nil
+}
+
+// printingTransport prints each request before sending it to next, or
+// instead of sending it when printOnly is set
+type printingTransport struct {
+	name      string
+	next      psa.Transport
+	printOnly bool
+}
+
+func (t *printingTransport) Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) error {
+	readable, err := url.QueryUnescape(cmd)
+	if err != nil {
+		readable = cmd
+	}
+	if body != nil {
+		data, _ := json.Marshal(body)
+		readable += " " + string(data)
+	}
+	fmt.Printf("%s: %s %s\n", t.name, method, readable)
+
+	if !t.printOnly {
+		return t.next.Do(ctx, method, cmd, body, v)
+	}
+
+	// Nothing is sent, so answer with an empty count or list. The board list
+	// holds the excluded boards, as the PSA client fails to start without
+	// them, while its sub-resources such as a board's teams stay empty.
+	path := cmd
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	var empty interface{} = []struct{}{}
+	switch {
+	case strings.HasSuffix(path, "/count"):
+		empty = map[string]int{"count": 0}
+	case path == "/service/boards":
+		boards := []psa.Board{}
+		for _, name := range excludeBoards {
+			boards = append(boards, psa.Board{Name: name})
+		}
+		empty = boards
+	}
+	data, _ := json.Marshal(empty)
+	return json.Unmarshal(data, v)
+}
+
+type cellChange struct {
+	sheet string
+	cell  string
+	old   string
+	new   string
+}
+
+// workbookValues returns the formatted value of every cell, keyed by sheet
+// and then cell reference
+func workbookValues(f *xlsx.File) map[string]map[string]string {
+	values := map[string]map[string]string{}
+	for _, sheet := range f.Sheets {
+		cells := map[string]string{}
+		for r, row := range sheet.Rows {
+			if row == nil {
+				continue
+			}
+			for col, cell := range row.Cells {
+				if cell == nil {
+					continue
+				}
+				v, err := cell.FormattedValue()
+				if err != nil {
+					v = cell.Value
+				}
+				if v != "" {
+					cells[cellRef(col, r)] = v
+				}
+			}
+		}
+		values[sheet.Name] = cells
+	}
+	return values
+}
+
+func diffValues(before, after map[string]map[string]string) []cellChange {
+	changes := []cellChange{}
+	for sheet, cells := range after {
+		for ref, v := range cells {
+			if old := before[sheet][ref]; old != v {
+				changes = append(changes, cellChange{sheet, ref, old, v})
+			}
+		}
+		for ref, old := range before[sheet] {
+			if _, ok := cells[ref]; !ok {
+				changes = append(changes, cellChange{sheet, ref, old, ""})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].sheet != changes[j].sheet {
+			return changes[i].sheet < changes[j].sheet
+		}
+		ri, ci := splitRef(changes[i].cell)
+		rj, cj := splitRef(changes[j].cell)
+		if ri != rj {
+			return ri < rj
+		}
+		return ci < cj
+	})
+	return changes
+}
+
+// cellRef returns the A1 style reference for zero based column and row
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return fmt.Sprintf("%s%d", name, row+1)
+}
+
+// splitRef returns the row number and column letters of a reference for
+// sorting, with shorter column names first
+func splitRef(ref string) (int, string) {
+	i := strings.IndexAny(ref, "0123456789")
+	row := 0
+	fmt.Sscanf(ref[i:], "%d", &row)
+	return row, fmt.Sprintf("%3s", ref[:i])
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+func TestPrintOnlyResponses(t *testing.T) {
+	tests := []struct {
+		cmd    string
+		boards bool
+		want   string
+	}{
+		{cmd: "/service/boards?pageSize=1000&page=1", boards: true},
+		{cmd: "/service/boards", boards: true},
+		{cmd: "/service/boards/12/teams?pageSize=1000&page=1", want: `[]`},
+		{cmd: "/service/boardsarchive", want: `[]`},
+		{cmd: "/service/tickets/count?conditions=ClosedFlag%20%3D%20False", want: `{"count":0}`},
+		{cmd: "/service/tickets?conditions=summary%20LIKE%20%27%2Fcount%27", want: `[]`},
+		{cmd: "sites", want: `[]`},
+	}
+
+	transport := &printingTransport{name: "PSA", printOnly: true}
+	for _, tt := range tests {
+		if tt.boards {
+			boards := []psa.Board{}
+			if err := transport.Do(context.Background(), "GET", tt.cmd, nil, &boards); err != nil {
+				t.Fatal(err)
+			}
+			if len(boards) != len(excludeBoards) || boards[0].Name != excludeBoards[0] {
+				t.Errorf("%s answered %+v, want the excluded boards", tt.cmd, boards)
+			}
+			continue
+		}
+
+		var v interface{}
+		if err := transport.Do(context.Background(), "GET", tt.cmd, nil, &v); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := json.Marshal(v); string(got) != tt.want {
+			t.Errorf("%s answered %s, want %s", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+// extraMetric is a board stat only collected for boards listing it in
+// extra_metrics, as it takes a request per ticket or is otherwise costly.
+// Extra stats follow the core stats in the board worksheet, each in a fixed
+// column whether or not the board collects it.
+type extraMetric struct {
+	boardMetric
+	// group is the extraCollectors entry that collects the stat
+	group string
+}
+
+var extraMetrics = []extraMetric{
+	{boardMetric{"firstResponseHours", "Avg Hours to First Response"}, "notes"},
+	{boardMetric{"touchesPerClosed", "Avg Touches per Closed Ticket"}, "notes"},
+	{boardMetric{"customerUpdated", "Customer Updated"}, "customerUpdated"},
+	{boardMetric{"closed", "Closed"}, "closed"},
+	{boardMetric{"resolutionMedianHours", "Median Hours to Resolve"}, "closed"},
+	{boardMetric{"resolutionP90Hours", "90th Percentile Hours to Resolve"}, "closed"},
+	{boardMetric{"reopened", "Reopened"}, "closed"},
+	{boardMetric{"reopenRate", "Reopen Rate %"}, "closed"},
+	{boardMetric{"slaRespondedPct", "Responded in SLA %"}, "sla"},
+	{boardMetric{"slaResolvedPct", "Resolved in SLA %"}, "sla"},
+	{boardMetric{"slaBreaching", "Breaching SLA"}, "sla"},
+	{boardMetric{"portalShare", "Portal Share %"}, "portalShare"},
+	{boardMetric{"notScheduled", "Not Scheduled"}, "schedule"},
+	{boardMetric{"scheduleOverdue", "Schedule Passed Without Update"}, "schedule"},
+	{boardMetric{"noHumanUpdate7", "No Member Update in 7 days"}, "humanUpdate"},
+}
+
+// extraCollector collects the extra stats in its group which the board has
+// enabled. Stats that cannot be worked out, such as an average of no
+// tickets, are left out and their cells left blank.
+type extraCollector func(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error)
+
+var extraCollectors = map[string]extraCollector{
+	"notes":           collectResponseStats,
+	"customerUpdated": collectCustomerUpdatedStats,
+	"closed":          collectClosedStats,
+	"sla":             collectSLAStats,
+	"portalShare":     collectPortalShareStats,
+	"schedule":        collectScheduleStats,
+	"humanUpdate":     collectHumanUpdateStats,
+}
+
+// extraStats holds the extra stats for a board, keyed by metric
+type extraStats map[string]float64
+
+func (b configBoards) hasExtra(metric string) bool {
+	for _, m := range b.ExtraMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+func findExtraMetric(key string) (extraMetric, bool) {
+	for _, m := range extraMetrics {
+		if m.Key == key {
+			return m, true
+		}
+	}
+	return extraMetric{}, false
+}
+
+func validateExtraMetrics(boards []configBoards) error {
+	for _, b := range boards {
+		for _, key := range b.ExtraMetrics {
+			if _, ok := findExtraMetric(key); !ok {
+				return fmt.Errorf("board %s: unknown extra metric %q", b.Name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// getExtraStatsForBoard runs each collector the board's extra metrics need
+func getExtraStatsForBoard(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	stats := extraStats{}
+	done := map[string]bool{}
+
+	for _, m := range extraMetrics {
+		if !board.hasExtra(m.Key) || done[m.group] {
+			continue
+		}
+		done[m.group] = true
+
+		group, err := extraCollectors[m.group](ctx, client, c, board)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range group {
+			stats[k] = v
+		}
+	}
+	return stats, nil
+}
+
+// writeExtraStats sets the board's extra stats in row, and labels any of
+// their columns missing from the header row. Extra columns already in row,
+// as when today's row is rewritten, are cleared first so a stat not
+// collected this run is left blank.
+func writeExtraStats(sheet *xlsx.Sheet, row *xlsx.Row, board configBoards, stats extraStats) {
+	for col := statCount + 1; col < len(row.Cells) && col <= statCount+len(extraMetrics); col++ {
+		row.Cells[col].SetString("")
+	}
+
+	for i, m := range extraMetrics {
+		if !board.hasExtra(m.Key) {
+			continue
+		}
+		col := statCount + 1 + i
+		for len(row.Cells) <= col {
+			row.AddCell()
+		}
+		if v, ok := stats[m.Key]; ok {
+			row.Cells[col].SetValue(roundStat(v))
+		}
+
+		header := sheet.Rows[0]
+		if header == row || !isHeaderRow(header) {
+			continue
+		}
+		for len(header.Cells) <= col {
+			header.AddCell()
+		}
+		if header.Cells[col].Value == "" {
+			header.Cells[col].SetValue(m.Label)
+		}
+	}
+}
+
+// isHeaderRow reports whether row is a header, rather than a row of stats
+// starting with a date
+func isHeaderRow(row *xlsx.Row) bool {
+	if row == nil || len(row.Cells) == 0 {
+		return false
+	}
+	date, err := row.Cells[0].GetTime(false)
+	return err != nil || date.IsZero()
+}
+
+// goalStatus returns the status of an extra stat against the board's goal
+// for printing, e.g. " (green)", or nothing if there is no goal
+func goalStatus(c config, boardName string, metric string, v float64) string {
+	board, ok := findBoard(c, boardName)
+	if !ok {
+		return ""
+	}
+	goal, ok := board.Goals[metric]
+	if !ok {
+		return ""
+	}
+	return " (" + goal.status(int(math.Round(v))) + ")"
+}
+
+// roundStat rounds averages to one decimal place for the worksheet
+func roundStat(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func formatStat(v float64) string {
+	return strconv.FormatFloat(roundStat(v), 'f', -1, 64)
+}
+
+// average returns the mean of values, false if there are none
+func average(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values)), true
+}
@@ -3,12 +3,8 @@ module github.com/simonbuckner/scorecard
 go 1.12
 
 require (
-	github.com/simononebyte/restup v0.0.0-20190911135854-81d615f2e651
 	github.com/simononebyte/scorecard/psa v0.0.0
 	github.com/tealeg/xlsx v1.0.5
 )
 
-replace (
-	github.com/simononebyte/restup => ../restup
-	github.com/simononebyte/scorecard/psa => ./psa
-)
+replace github.com/simononebyte/scorecard/psa => ./psa
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+// snapshot is one row of a board worksheet
+type snapshot struct {
+	Date  time.Time      `json:"date"`
+	Stats map[string]int `json:"stats"`
+}
+
+// boardHistory is every snapshot saved for a board, oldest first
+type boardHistory struct {
+	Board     string     `json:"board"`
+	Snapshots []snapshot `json:"snapshots"`
+}
+
+// latest returns the most recent snapshot, or false if there are none
+func (h boardHistory) latest() (snapshot, bool) {
+	if len(h.Snapshots) == 0 {
+		return snapshot{}, false
+	}
+	return h.Snapshots[len(h.Snapshots)-1], true
+}
+
+// readHistory reads the saved stats for each configured board from the
+// workbook. Rows without a date in the first column (headers) are skipped.
+func readHistory(c config) ([]boardHistory, error) {
+	f, err := xlsx.OpenFile(c.StatsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]boardHistory, 0, len(c.Boards))
+	for _, board := range c.Boards {
+		sheet := getSheet(f, board.Worksheet)
+		if sheet == nil {
+			return nil, fmt.Errorf("error: unable to find worksheet %v", board.Worksheet)
+		}
+		history = append(history, boardHistory{
+			Board:     board.Name,
+			Snapshots: readSnapshots(sheet),
+		})
+	}
+	return history, nil
+}
+
+func readSnapshots(sheet *xlsx.Sheet) []snapshot {
+	snapshots := []snapshot{}
+	for _, row := range sheet.Rows {
+		if row == nil || len(row.Cells) == 0 {
+			continue
+		}
+		date, err := row.Cells[0].GetTime(false)
+		if err != nil || date.IsZero() {
+			continue
+		}
+
+		s := snapshot{Date: date, Stats: make(map[string]int)}
+		for i, m := range boardMetrics {
+			if i+1 >= len(row.Cells) {
+				break
+			}
+			v, err := row.Cells[i+1].Int()
+			if err != nil {
+				continue
+			}
+			s.Stats[m.Key] = v
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots
+}
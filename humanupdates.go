@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// humanUpdateFields are the ticket fields needed to find who last updated it
+var humanUpdateFields = []string{"id", "_info/lastUpdated", "_info/updatedBy"}
+
+// isAutomation reports whether the member identifier is one of the
+// automation_members, such as an API or integration login
+func (c config) isAutomation(identifier string) bool {
+	for _, m := range c.AutomationMembers {
+		if strings.EqualFold(m, identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectHumanUpdateStats counts the open tickets with no update by a
+// person in the last week, noHumanUpdate7. Unlike noUpdate7, notes and
+// changes made by the automation_members don't count as an update.
+func collectHumanUpdateStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	tickets, err := client.GetOpenTicketsByBoardIDContext(ctx, board.ID, humanUpdateFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	since := clock().AddDate(0, 0, -7)
+	n := 0
+	for _, t := range tickets {
+		if t.Info.LastUpdated.After(since) {
+			// Only tickets last updated by automation need their notes and
+			// audit trail checking for an earlier update by a person
+			if !c.isAutomation(t.Info.UpdatedBy) {
+				continue
+			}
+			updated, err := hasHumanUpdateSince(ctx, client, c, t.ID, since)
+			if err != nil {
+				return nil, err
+			}
+			if updated {
+				continue
+			}
+		}
+		n++
+	}
+	return extraStats{"noHumanUpdate7": float64(n)}, nil
+}
+
+// hasHumanUpdateSince reports whether a member other than the
+// automation_members has added a note or changed the ticket since the given
+// time
+func hasHumanUpdateSince(ctx context.Context, client *psa.Client, c config, ticketID int, since time.Time) (bool, error) {
+	notes, err := client.GetTicketNotesContext(ctx, ticketID)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range notes {
+		if n.Member.ID != 0 && n.DateCreated.After(since) && !c.isAutomation(n.CreatedBy) {
+			return true, nil
+		}
+	}
+
+	audit, err := client.GetTicketAuditTrailContext(ctx, ticketID)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range audit {
+		if a.EnteredDate.After(since) && !c.isAutomation(a.EnteredBy) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
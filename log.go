@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+const (
+	defaultLogLevel      = "info"
+	defaultLogFormat     = "text"
+	defaultLogMaxSizeMB  = 10
+	defaultLogMaxBackups = 5
+
+	redacted = "[REDACTED]"
+)
+
+type configLog struct {
+	Level      string `json:"level"`
+	Format     string `json:"format"`
+	File       string `json:"file"`
+	MaxSizeMB  int    `json:"max_size_mb"`
+	MaxBackups int    `json:"max_backups"`
+}
+
+func (l configLog) level() (logLevel, error) {
+	if l.Level == "" {
+		return parseLogLevel(defaultLogLevel)
+	}
+	return parseLogLevel(l.Level)
+}
+
+func (l configLog) format() string {
+	if l.Format == "" {
+		return defaultLogFormat
+	}
+	return l.Format
+}
+
+func (l configLog) maxSize() int64 {
+	if l.MaxSizeMB <= 0 {
+		return defaultLogMaxSizeMB << 20
+	}
+	return int64(l.MaxSizeMB) << 20
+}
+
+func (l configLog) maxBackups() int {
+	if l.MaxBackups <= 0 {
+		return defaultLogMaxBackups
+	}
+	return l.MaxBackups
+}
+
+func (l configLog) validate() error {
+	if _, err := l.level(); err != nil {
+		return err
+	}
+	if f := l.format(); f != "text" && f != "json" {
+		return fmt.Errorf("invalid log format %q, must be text or json", f)
+	}
+	return nil
+}
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+func (l logLevel) String() string {
+	return logLevelNames[l]
+}
+
+func parseLogLevel(s string) (logLevel, error) {
+	for i, name := range logLevelNames {
+		if strings.EqualFold(s, name) {
+			return logLevel(i), nil
+		}
+	}
+	return levelInfo, fmt.Errorf("invalid log level %q", s)
+}
+
+// leveledLogger writes log lines as text or JSON. Each line has a level, a
+// message and a list of key value pairs. Any configured secret appearing in
+// a message or value is replaced before the line is written.
+type leveledLogger struct {
+	mu      sync.Mutex
+	out     io.Writer
+	level   logLevel
+	json    bool
+	secrets []string
+}
+
+// logger is used throughout. Until the config is read it writes info and
+// above as text to stderr.
+var logger = &leveledLogger{out: os.Stderr, level: levelInfo}
+
+// setupLogging configures logger from the config, and hooks the PSA client up
+// to log each request at debug level
+func setupLogging(c *config) error {
+	level, err := c.Log.level()
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stderr)
+	if c.Log.File != "" {
+		f, err := openRotatingFile(c.Log.File, c.Log.maxSize(), c.Log.maxBackups())
+		if err != nil {
+			return fmt.Errorf("error opening log file: %s", err)
+		}
+		out = f
+	}
+
+	logger.mu.Lock()
+	logger.out = out
+	logger.level = level
+	logger.json = c.Log.format() == "json"
+	logger.secrets = credentials(*c)
+	logger.mu.Unlock()
+
+	c.ConnectWise.OnRequest = logRequest("psa")
+	return nil
+}
+
+// credentials lists the secrets in the config, including the encoded forms
+// sent in the Authorization header
+func credentials(c config) []string {
+	secrets := []string{}
+	add := func(s string) {
+		if s != "" {
+			secrets = append(secrets, s, base64.StdEncoding.EncodeToString([]byte(s)))
+		}
+	}
+	psaToken := fmt.Sprintf("%s+%s:%s", c.ConnectWise.Company, c.ConnectWise.Username, c.ConnectWise.Password)
+	add(psaToken)
+	add(c.ConnectWise.Password)
+	add(c.ConnectWise.Username)
+	add(c.Continuum)
+
+	// Longest first so a secret containing another is replaced whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	return secrets
+}
+
+// logRequest returns a psa.HTTPTransport OnRequest hook that logs each
+// request made to the named API
+func logRequest(api string) func(psa.RequestLog) {
+	return func(r psa.RequestLog) {
+		kv := []interface{}{
+			"api", api,
+			"method", r.Method,
+			"endpoint", r.Endpoint,
+		}
+		if r.Conditions != "" {
+			kv = append(kv, "conditions", r.Conditions)
+		}
+		if r.Page > 0 {
+			kv = append(kv, "page", r.Page)
+		}
+		kv = append(kv, "status", r.Status, "duration", r.Duration.Round(time.Millisecond))
+		if r.Err != nil {
+			kv = append(kv, "error", r.Err)
+		}
+		logger.Debug("request", kv...)
+	}
+}
+
+// Debug logs msg with key value pairs at debug level
+func (l *leveledLogger) Debug(msg string, kv ...interface{}) { l.write(levelDebug, msg, kv) }
+
+// Info logs msg with key value pairs at info level
+func (l *leveledLogger) Info(msg string, kv ...interface{}) { l.write(levelInfo, msg, kv) }
+
+// Warn logs msg with key value pairs at warn level
+func (l *leveledLogger) Warn(msg string, kv ...interface{}) { l.write(levelWarn, msg, kv) }
+
+// Error logs msg with key value pairs at error level
+func (l *leveledLogger) Error(msg string, kv ...interface{}) { l.write(levelError, msg, kv) }
+
+// Warnf logs a formatted message at warn level, for psa.RetryPolicy.Logf
+func (l *leveledLogger) Warnf(format string, a ...interface{}) {
+	l.write(levelWarn, fmt.Sprintf(format, a...), nil)
+}
+
+func (l *leveledLogger) write(level logLevel, msg string, kv []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level {
+		return
+	}
+
+	now := time.Now()
+	var line []byte
+	if l.json {
+		line = l.jsonLine(now, level, msg, kv)
+	} else {
+		line = l.textLine(now, level, msg, kv)
+	}
+	l.out.Write(line)
+}
+
+func (l *leveledLogger) textLine(now time.Time, level logLevel, msg string, kv []interface{}) []byte {
+	var b strings.Builder
+	b.WriteString(now.Format(time.RFC3339))
+	b.WriteString(" ")
+	b.WriteString(strings.ToUpper(level.String()))
+	b.WriteString(" ")
+	b.WriteString(l.redact(msg))
+	for i := 0; i < len(kv); i += 2 {
+		b.WriteString(" ")
+		b.WriteString(fmt.Sprint(kv[i]))
+		b.WriteString("=")
+		b.WriteString(quoteValue(l.redact(formatValue(kvValue(kv, i+1)))))
+	}
+	b.WriteString("\n")
+	return []byte(b.String())
+}
+
+func (l *leveledLogger) jsonLine(now time.Time, level logLevel, msg string, kv []interface{}) []byte {
+	var b strings.Builder
+	writeField := func(k string, v interface{}) {
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(v)
+		if err != nil {
+			val, _ = json.Marshal(fmt.Sprint(v))
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(val)
+	}
+
+	b.WriteString("{")
+	writeField("time", now.Format(time.RFC3339))
+	b.WriteString(",")
+	writeField("level", level.String())
+	b.WriteString(",")
+	writeField("msg", l.redact(msg))
+	for i := 0; i < len(kv); i += 2 {
+		b.WriteString(",")
+		v := kvValue(kv, i+1)
+		switch v.(type) {
+		case int, int64, float64, bool:
+		default:
+			v = l.redact(formatValue(v))
+		}
+		writeField(fmt.Sprint(kv[i]), v)
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+func kvValue(kv []interface{}, i int) interface{} {
+	if i < len(kv) {
+		return kv[i]
+	}
+	return "(missing)"
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+func quoteValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func (l *leveledLogger) redact(s string) string {
+	for _, secret := range l.secrets {
+		s = strings.Replace(s, secret, redacted, -1)
+	}
+	return s
+}
+
+// rotatingFile is a log file that is rotated once it reaches maxSize, keeping
+// maxBackups old files as path.1 (newest) to path.N
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	f          *os.File
+	size       int64
+
+	// limit is the size that triggers the next rotation, pushed back by
+	// maxSize when a rotation fails so it is not retried on every write
+	limit int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups, limit: maxSize}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write is only called with the logger's lock held
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(p)) > r.limit {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the log to path.1 and starts a new one. If that fails the
+// error is reported on stderr and logging carries on in path, reopened for
+// appending.
+func (r *rotatingFile) rotate() error {
+	err := r.f.Close()
+	if err == nil {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		err = os.Rename(r.path, r.path+".1")
+	}
+	if err == nil {
+		if err = r.open(); err == nil {
+			r.limit = r.maxSize
+			return nil
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "error rotating log file %s: %s\n", r.path, err)
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.limit = r.size + r.maxSize
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type configMetrics struct {
+	Interval string `json:"interval"`
+}
+
+// interval returns how often the metrics are refreshed, zero if disabled
+func (m configMetrics) interval() (time.Duration, error) {
+	if m.Interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(m.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid metrics interval: %s", err)
+	}
+	if d < time.Minute {
+		return 0, fmt.Errorf("metrics interval %s is less than 1m", d)
+	}
+	return d, nil
+}
+
+// collectorHealth tracks the outcome of refreshes for one API
+type collectorHealth struct {
+	lastSuccess time.Time
+	errors      int
+	duration    time.Duration
+	up          bool
+}
+
+// metricsRegistry holds the latest board and RMM stats for /metrics
+type metricsRegistry struct {
+	mu         sync.Mutex
+	boards     boardStatsMap
+	rmm        *RMMStats
+	collectors map[string]*collectorHealth
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		collectors: map[string]*collectorHealth{
+			"psa": {},
+			"rmm": {},
+		},
+	}
+}
+
+// run refreshes the metrics immediately and then every interval
+func (m *metricsRegistry) run(ctx context.Context, c config, interval time.Duration) {
+	for {
+		m.refresh(ctx, c)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *metricsRegistry) refresh(ctx context.Context, c config) {
+	start := time.Now()
+	boards, err := collectStats(ctx, c)
+	m.record("psa", start, err)
+	if err == nil {
+		m.mu.Lock()
+		m.boards = boards
+		m.mu.Unlock()
+	} else {
+		logger.Error("error refreshing metrics", "collector", "psa", "error", err)
+	}
+
+	start = time.Now()
+	rmm, err := NewRMMClient(c).GetRMMStats(ctx)
+	m.record("rmm", start, err)
+	if err == nil {
+		m.mu.Lock()
+		m.rmm = &rmm
+		m.mu.Unlock()
+	} else {
+		logger.Error("error refreshing metrics", "collector", "rmm", "error", err)
+	}
+}
+
+func (m *metricsRegistry) record(collector string, start time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.collectors[collector]
+	h.duration = time.Since(start)
+	h.up = err == nil
+	if err != nil {
+		h.errors++
+		return
+	}
+	h.lastSuccess = time.Now()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (m *metricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	writeMetricHeader(w, "scorecard_board_tickets", "gauge", "Tickets per service board and metric")
+	boards := make([]string, 0, len(m.boards))
+	for name := range m.boards {
+		boards = append(boards, name)
+	}
+	sort.Strings(boards)
+	for _, name := range boards {
+		values := m.boards[name].values()
+		for i, metric := range boardMetrics {
+			writeMetric(w, "scorecard_board_tickets", float64(values[i]),
+				"board", name, "metric", metric.Key)
+		}
+	}
+
+	writeMetricHeader(w, "scorecard_board_extra", "gauge", "Extra stats per service board and metric")
+	for _, name := range boards {
+		extra := m.boards[name].extra
+		for _, metric := range extraMetrics {
+			if v, ok := extra[metric.Key]; ok {
+				writeMetric(w, "scorecard_board_extra", v, "board", name, "metric", metric.Key)
+			}
+		}
+	}
+
+	writeMetricHeader(w, "scorecard_rmm_devices", "gauge", "RMM devices by site class")
+	if m.rmm != nil {
+		writeMetric(w, "scorecard_rmm_devices", float64(m.rmm.TSCDevices), "class", "tsc")
+		writeMetric(w, "scorecard_rmm_devices", float64(m.rmm.OtherDevices), "class", "other")
+	}
+
+	collectors := []string{"psa", "rmm"}
+
+	writeMetricHeader(w, "scorecard_collector_up", "gauge", "Whether the last refresh succeeded")
+	for _, name := range collectors {
+		up := 0.0
+		if m.collectors[name].up {
+			up = 1
+		}
+		writeMetric(w, "scorecard_collector_up", up, "collector", name)
+	}
+
+	writeMetricHeader(w, "scorecard_collector_last_success_timestamp_seconds", "gauge",
+		"Unix time of the last successful refresh")
+	for _, name := range collectors {
+		h := m.collectors[name]
+		if h.lastSuccess.IsZero() {
+			continue
+		}
+		writeMetric(w, "scorecard_collector_last_success_timestamp_seconds",
+			float64(h.lastSuccess.Unix()), "collector", name)
+	}
+
+	writeMetricHeader(w, "scorecard_collector_errors_total", "counter", "Refreshes that failed")
+	for _, name := range collectors {
+		writeMetric(w, "scorecard_collector_errors_total", float64(m.collectors[name].errors),
+			"collector", name)
+	}
+
+	writeMetricHeader(w, "scorecard_collector_duration_seconds", "gauge", "Duration of the last refresh")
+	for _, name := range collectors {
+		writeMetric(w, "scorecard_collector_duration_seconds", m.collectors[name].duration.Seconds(),
+			"collector", name)
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeMetric writes a sample, labels are given as name, value pairs
+func writeMetric(w io.Writer, name string, value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeLabel(labels[i+1])))
+	}
+	fmt.Fprintf(w, "%s{%s} %g\n", name, strings.Join(pairs, ","), value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -1,17 +1,24 @@
 package psa
 
 import (
+	"context"
 	"fmt"
 )
 
 const (
-	boardsEndpoint string = "/service/boards"
+	boardsEndpoint     string = "/service/boards"
+	boardTeamsEndpoint string = "/service/boards/%v/teams"
 )
 
 // GetBoards get the service boards currently active
 func (c *Client) GetBoards() ([]Board, error) {
+	return c.GetBoardsContext(context.Background())
+}
+
+// GetBoardsContext is GetBoards with a context
+func (c *Client) GetBoardsContext(ctx context.Context) ([]Board, error) {
 
-	boards, err := c.getBoardCommand(boardsEndpoint)
+	boards, err := c.getBoardCommand(ctx, boardsEndpoint)
 	if err != nil {
 		return []Board{}, err
 	}
@@ -21,8 +28,13 @@ func (c *Client) GetBoards() ([]Board, error) {
 
 // GetBoardID get ID for a service board
 func (c *Client) GetBoardID(boardName string) (int, error) {
+	return c.GetBoardIDContext(context.Background(), boardName)
+}
 
-	boards, err := c.getBoardCommand(boardsEndpoint)
+// GetBoardIDContext is GetBoardID with a context
+func (c *Client) GetBoardIDContext(ctx context.Context, boardName string) (int, error) {
+
+	boards, err := c.getBoardCommand(ctx, boardsEndpoint)
 	if err != nil {
 		return -1, err
 	}
@@ -35,3 +47,71 @@ func (c *Client) GetBoardID(boardName string) (int, error) {
 
 	return -1, fmt.Errorf("service board not found")
 }
+
+// GetTeams gets the service teams on every active board
+func (c *Client) GetTeams() ([]Team, error) {
+	return c.GetTeamsContext(context.Background())
+}
+
+// GetTeamsContext is GetTeams with a context
+func (c *Client) GetTeamsContext(ctx context.Context) ([]Team, error) {
+
+	boards, err := c.getBoardCommand(ctx, boardsEndpoint)
+	if err != nil {
+		return []Team{}, err
+	}
+
+	teams := []Team{}
+	for _, b := range boards {
+		if c.isExcludedBoard(b.ID) {
+			continue
+		}
+		boardTeams, err := c.GetBoardTeamsContext(ctx, b.ID)
+		if err != nil {
+			return []Team{}, err
+		}
+		teams = append(teams, boardTeams...)
+	}
+
+	return teams, nil
+}
+
+// GetBoardTeams gets the service teams on a board
+// boardID: The PSA board ID
+func (c *Client) GetBoardTeams(boardID int) ([]Team, error) {
+	return c.GetBoardTeamsContext(context.Background(), boardID)
+}
+
+// GetBoardTeamsContext is GetBoardTeams with a context
+func (c *Client) GetBoardTeamsContext(ctx context.Context, boardID int) ([]Team, error) {
+
+	teams, err := c.getTeamsCommand(ctx, fmt.Sprintf(boardTeamsEndpoint, boardID))
+	if err != nil {
+		return []Team{}, err
+	}
+
+	return teams, nil
+}
+
+// GetTeamMembers resolves the members of a team
+func (c *Client) GetTeamMembers(team Team) ([]Member, error) {
+	return c.GetTeamMembersContext(context.Background(), team)
+}
+
+// GetTeamMembersContext is GetTeamMembers with a context
+func (c *Client) GetTeamMembersContext(ctx context.Context, team Team) ([]Member, error) {
+
+	members, err := c.getMembersCommand(ctx, activeMembersEndpoint)
+	if err != nil {
+		return []Member{}, err
+	}
+
+	teamMembers := []Member{}
+	for _, m := range members {
+		if team.HasMember(m.ID) {
+			teamMembers = append(teamMembers, m)
+		}
+	}
+
+	return teamMembers, nil
+}
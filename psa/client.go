@@ -1,11 +1,13 @@
 package psa
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
-
-	"github.com/simononebyte/restup"
 )
 
 // Config holds the API credentials for the PSA system
@@ -15,11 +17,27 @@ type Config struct {
 	Password string `json:"private"`
 	ClientID string `json:"client_id"`
 	APIBase  string `json:"api_base"`
+
+	// Retry is set by the caller, the defaults are used when left empty
+	Retry RetryPolicy `json:"-"`
+
+	// Transport is set by the caller to replace the default HTTPTransport,
+	// e.g. with one pointing at a psatest.Server
+	Transport Transport `json:"-"`
+
+	// Clock is set by the caller to pin the time queries are relative to,
+	// time.Now is used when nil
+	Clock func() time.Time `json:"-"`
+
+	// OnRequest is passed to the default HTTPTransport
+	OnRequest func(RequestLog) `json:"-"`
 }
 
 // Client ...
 type Client struct {
-	restup        *restup.RestUp
+	transport     Transport
+	retry         RetryPolicy
+	now           func() time.Time
 	excludeBoards []Board
 }
 
@@ -47,22 +65,40 @@ const (
 //  globalBoardExcludes is a list of service boards that will be
 //  excluded from all qureies
 func NewClient(c Config, globalBoardExcludes []string) (*Client, error) {
-	token := fmt.Sprintf("%s+%s:%s", c.Company, c.Username, c.Password)
+	return NewClientContext(context.Background(), c, globalBoardExcludes)
+}
 
-	client := &Client{}
-	client.restup = restup.NewRestUp(c.APIBase, token)
-	client.restup.AddHeader("clientId", c.ClientID)
+// NewClientContext is NewClient with a context for looking up the excluded
+// service boards
+func NewClientContext(ctx context.Context, c Config, globalBoardExcludes []string) (*Client, error) {
+	client := &Client{retry: c.Retry, transport: c.Transport, now: c.Clock}
+	if client.transport == nil {
+		client.transport = NewDefaultTransport(c)
+	}
+	if client.now == nil {
+		client.now = time.Now
+	}
 
 	if len(globalBoardExcludes) > 0 {
-		if err := client.populateExcludes(globalBoardExcludes); err != nil {
+		if err := client.populateExcludes(ctx, globalBoardExcludes); err != nil {
 			return &Client{}, err
 		}
 	}
 	return client, nil
 }
 
-func (c *Client) populateExcludes(excludes []string) error {
-	boards, err := c.GetBoards()
+// NewDefaultTransport creates the HTTPTransport a client uses when no
+// Transport is configured
+func NewDefaultTransport(c Config) *HTTPTransport {
+	token := fmt.Sprintf("%s+%s:%s", c.Company, c.Username, c.Password)
+	t := NewHTTPTransport(c.APIBase, token)
+	t.Headers["clientId"] = c.ClientID
+	t.OnRequest = c.OnRequest
+	return t
+}
+
+func (c *Client) populateExcludes(ctx context.Context, excludes []string) error {
+	boards, err := c.GetBoardsContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -81,39 +117,75 @@ func (c *Client) populateExcludes(excludes []string) error {
 	return nil
 }
 
+// get runs a GET request, retrying transient failures
+func (c *Client) get(ctx context.Context, cmd string, v interface{}) error {
+	return c.retry.Do(ctx, "GET "+cmd, v, func(ctx context.Context, v interface{}) error {
+		return c.transport.Do(ctx, http.MethodGet, cmd, nil, v)
+	})
+}
+
+// post runs a POST request, retrying transient failures
+func (c *Client) post(ctx context.Context, cmd string, query map[string]string, v interface{}) error {
+	return c.retry.Do(ctx, "POST "+cmd, v, func(ctx context.Context, v interface{}) error {
+		return c.transport.Do(ctx, http.MethodPost, cmd, query, v)
+	})
+}
+
+// quoteReplacer escapes the characters that would end a quoted conditions
+// value early
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quote returns s as a double quoted conditions value, so names containing
+// quotes or backslashes can be matched
+func quote(s string) string {
+	return `"` + quoteReplacer.Replace(s) + `"`
+}
+
 func newCondition(condition string, a ...interface{}) map[string]string {
 	conditions := make(map[string]string)
 	conditions["conditions"] = fmt.Sprintf(condition, a...)
 	return conditions
 }
 
-func dateStringFromDays(days int) string {
+// pageCommand adds the paging and optional field list parameters to cmd
+func pageCommand(cmd string, pageSize int, page int, fields []string) string {
+	sep := "?"
+	if strings.Contains(cmd, "?") {
+		sep = "&"
+	}
+	cmd = fmt.Sprintf("%s%spageSize=%d&page=%d", cmd, sep, pageSize, page)
+	if len(fields) > 0 {
+		cmd += "&fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+	return cmd
+}
+
+func (c *Client) dateStringFromDays(days int) string {
 	if days > 0 {
 		days = days * -1
 	}
-	date := time.Now().AddDate(0, 0, days)
+	date := c.now().AddDate(0, 0, days)
 	return fmt.Sprintf("%d-%d-%d", date.Year(), date.Month(), date.Day())
 }
 
 // getBoardCommand runs a Service Board GET API query
-func (c *Client) getBoardCommand(cmd string) ([]Board, error) {
+func (c *Client) getBoardCommand(ctx context.Context, cmd string) ([]Board, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	boards := []Board{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []Board{}
 
-		if err := c.restup.Get(cmd, &page); err != nil {
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
 			return []Board{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		boards = append(boards, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -123,24 +195,23 @@ func (c *Client) getBoardCommand(cmd string) ([]Board, error) {
 }
 
 // postBoardCommand runs a Service Board POST API query
-func (c *Client) postBoardCommand(cmd string, query map[string]string) ([]Board, error) {
+func (c *Client) postBoardCommand(ctx context.Context, cmd string, query map[string]string) ([]Board, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	boards := []Board{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []Board{}
 
-		if err := c.restup.Post(cmd, query, &page); err != nil {
+		if err := c.post(ctx, pageCommand(cmd, pageSize, currentPage, nil), query, &page); err != nil {
 			return []Board{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		boards = append(boards, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -150,24 +221,23 @@ func (c *Client) postBoardCommand(cmd string, query map[string]string) ([]Board,
 }
 
 // getCommand runs a getCommand
-func (c *Client) getTicketsCommand(cmd string) ([]Ticket, error) {
+func (c *Client) getTicketsCommand(ctx context.Context, cmd string, fields []string) ([]Ticket, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	tickets := []Ticket{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []Ticket{}
 
-		if err := c.restup.Get(cmd, &page); err != nil {
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, fields), &page); err != nil {
 			return []Ticket{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		tickets = append(tickets, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -177,24 +247,23 @@ func (c *Client) getTicketsCommand(cmd string) ([]Ticket, error) {
 }
 
 // postTicketsCommand runs a POST API query
-func (c *Client) postTicketsCommand(cmd string, query map[string]string) ([]Ticket, error) {
+func (c *Client) postTicketsCommand(ctx context.Context, cmd string, query map[string]string, fields []string) ([]Ticket, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	tickets := []Ticket{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []Ticket{}
 
-		if err := c.restup.Post(cmd, query, &page); err != nil {
+		if err := c.post(ctx, pageCommand(cmd, pageSize, currentPage, fields), query, &page); err != nil {
 			return []Ticket{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		tickets = append(tickets, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -204,24 +273,23 @@ func (c *Client) postTicketsCommand(cmd string, query map[string]string) ([]Tick
 }
 
 // getMembersCommand runs a getCommand
-func (c *Client) getMembersCommand(cmd string) ([]Member, error) {
+func (c *Client) getMembersCommand(ctx context.Context, cmd string) ([]Member, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	members := []Member{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []Member{}
 
-		if err := c.restup.Get(cmd, &page); err != nil {
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
 			return []Member{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		members = append(members, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -230,6 +298,15 @@ func (c *Client) getMembersCommand(cmd string) ([]Member, error) {
 	return members, nil
 }
 
+func (c *Client) isExcludedBoard(boardID int) bool {
+	for _, b := range c.excludeBoards {
+		if b.ID == boardID {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) wrapExcludedBoards(condition string) string {
 	newCondition := "((" + condition + ")"
 	for _, v := range c.excludeBoards {
@@ -239,24 +316,23 @@ func (c *Client) wrapExcludedBoards(condition string) string {
 }
 
 // getTicketSourceCommand runs a getCommand
-func (c *Client) getTicketSourceCommand(cmd string) ([]TicketSource, error) {
+func (c *Client) getTicketSourceCommand(ctx context.Context, cmd string) ([]TicketSource, error) {
 
 	pageSize := 1000
 	currentPage := 1
 	sources := []TicketSource{}
 
 	for {
-		cmd = fmt.Sprintf("%s?pageSize=%d&page=%d", cmd, pageSize, currentPage)
 		page := []TicketSource{}
 
-		if err := c.restup.Get(cmd, &page); err != nil {
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
 			return []TicketSource{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		sources = append(sources, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
@@ -265,8 +341,154 @@ func (c *Client) getTicketSourceCommand(cmd string) ([]TicketSource, error) {
 	return sources, nil
 }
 
-// getMembersCommand runs a getCommand
-func (c *Client) getAuditTrailCommand(cmd string) ([]Audit, error) {
+// getTicketNotesCommand runs a getCommand
+func (c *Client) getTicketNotesCommand(ctx context.Context, cmd string) ([]TicketNote, error) {
+
+	pageSize := 1000
+	currentPage := 1
+	notes := []TicketNote{}
+
+	for {
+		page := []TicketNote{}
+
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
+			return []TicketNote{}, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		notes = append(notes, page...)
+		if len(page) < pageSize {
+			break
+		}
+		currentPage++
+	}
+
+	return notes, nil
+}
+
+// getSLAPrioritiesCommand runs a getCommand
+func (c *Client) getSLAPrioritiesCommand(ctx context.Context, cmd string) ([]SLAPriority, error) {
+
+	pageSize := 1000
+	currentPage := 1
+	priorities := []SLAPriority{}
+
+	for {
+		page := []SLAPriority{}
+
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
+			return []SLAPriority{}, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		priorities = append(priorities, page...)
+		if len(page) < pageSize {
+			break
+		}
+		currentPage++
+	}
+
+	return priorities, nil
+}
+
+// getTeamsCommand runs a getCommand
+func (c *Client) getTeamsCommand(ctx context.Context, cmd string) ([]Team, error) {
+
+	pageSize := 1000
+	currentPage := 1
+	teams := []Team{}
+
+	for {
+		page := []Team{}
+
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
+			return []Team{}, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		teams = append(teams, page...)
+		if len(page) < pageSize {
+			break
+		}
+		currentPage++
+	}
+
+	return teams, nil
+}
+
+// getTimeEntriesCommand runs a getCommand
+func (c *Client) getTimeEntriesCommand(ctx context.Context, cmd string, fields []string) ([]TimeEntry, error) {
+
+	pageSize := 1000
+	currentPage := 1
+	entries := []TimeEntry{}
+
+	for {
+		page := []TimeEntry{}
+
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, fields), &page); err != nil {
+			return []TimeEntry{}, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		entries = append(entries, page...)
+		if len(page) < pageSize {
+			break
+		}
+		currentPage++
+	}
+
+	return entries, nil
+}
+
+// getScheduleEntriesCommand runs a getCommand
+func (c *Client) getScheduleEntriesCommand(ctx context.Context, cmd string, fields []string) ([]ScheduleEntry, error) {
+
+	pageSize := 1000
+	currentPage := 1
+	entries := []ScheduleEntry{}
+
+	for {
+		page := []ScheduleEntry{}
+
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, fields), &page); err != nil {
+			return []ScheduleEntry{}, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		entries = append(entries, page...)
+		if len(page) < pageSize {
+			break
+		}
+		currentPage++
+	}
+
+	return entries, nil
+}
+
+// countCommand runs a count API query, returning just the number of records
+// that match the conditions
+func (c *Client) countCommand(ctx context.Context, cmd string, conditions string) (int, error) {
+
+	count := struct {
+		Count int `json:"count"`
+	}{}
+
+	cmd = fmt.Sprintf("%s?conditions=%s", cmd, url.QueryEscape(conditions))
+	if err := c.get(ctx, cmd, &count); err != nil {
+		return 0, err
+	}
+
+	return count.Count, nil
+}
+
+// getAuditTrailCommand runs a getCommand
+func (c *Client) getAuditTrailCommand(ctx context.Context, cmd string) ([]Audit, error) {
 
 	pageSize := 1000
 	currentPage := 1
@@ -275,14 +497,14 @@ func (c *Client) getAuditTrailCommand(cmd string) ([]Audit, error) {
 	for {
 		page := []Audit{}
 
-		if err := c.restup.Get(cmd, &page); err != nil {
+		if err := c.get(ctx, pageCommand(cmd, pageSize, currentPage, nil), &page); err != nil {
 			return []Audit{}, err
 		}
 		if len(page) == 0 {
 			break
 		}
 		audit = append(audit, page...)
-		if len(page) <= pageSize {
+		if len(page) < pageSize {
 			break
 		}
 		currentPage++
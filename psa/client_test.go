@@ -0,0 +1,246 @@
+package psa_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/simononebyte/scorecard/psa/psatest"
+)
+
+// newSystemServer starts a psatest.Server holding members, teams and the
+// records belonging to tickets, with boards named as the scorecard uses them
+func newSystemServer(t *testing.T) (*psatest.Server, *psa.Client) {
+	t.Helper()
+	s := psatest.NewServer()
+	s.Boards = []psa.Board{{ID: 1, Name: "Reactive"}, {ID: 2, Name: "Projects"}, {ID: 9, Name: "Planned Time Off"}}
+	s.Members = []psa.Member{
+		{ID: 10, Identifier: "jbloggs", Name: "Joe Bloggs"},
+		{ID: 11, Identifier: "asmith", Name: "Alex Smith"},
+		{ID: 12, Identifier: "left", Name: "Has Left"},
+		{ID: 13, Identifier: "APIMember", Name: "API Member"},
+	}
+	s.DisabledMembers = []int{12}
+	s.UntypedMembers = []int{13}
+	s.Teams = []psa.Team{
+		{ID: 5, Name: "Helpdesk", BoardID: 1, Members: []int{10, 12}},
+		{ID: 6, Name: "Projects", BoardID: 2, Members: []int{11}},
+		{ID: 7, Name: "Holidays", BoardID: 9, Members: []int{10, 11}},
+	}
+	s.Tickets = []psa.Ticket{{ID: 100, Board: s.Boards[0]}}
+	s.Notes[100] = []psa.TicketNote{{ID: 1, TicketID: 100, Text: "Printer offline", DetailDescriptionFlag: true}}
+	s.Audit[100] = []psa.Audit{{Text: `Status has been updated from "New" to "In Progress".`, EnteredBy: "jbloggs"}}
+	s.Sources = []psa.TicketSource{{ID: 1, Name: "Email"}, {ID: 2, Name: "Portal"}}
+	s.SLAPriorities[3] = []psa.SLAPriority{{ID: 1, Priority: psa.Reference{ID: 8}, RespondHours: 2, ResolutionHours: 16}}
+
+	c := s.Config()
+	c.Clock = func() time.Time { return now }
+	client, err := psa.NewClient(c, []string{"Planned Time Off"})
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func TestNewClientMissingExclude(t *testing.T) {
+	s := psatest.NewServer()
+	defer s.Close()
+	s.Boards = []psa.Board{{ID: 1, Name: "Reactive"}}
+
+	if _, err := psa.NewClient(s.Config(), []string{"Planned Time Off"}); err == nil {
+		t.Error("created a client when an excluded board is missing")
+	}
+}
+
+func TestClientHTTPError(t *testing.T) {
+	s := psatest.NewServer()
+	defer s.Close()
+
+	c := s.Config()
+	c.APIBase += "/missing"
+	client, err := psa.NewClient(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetBoards()
+	se, ok := err.(*psa.StatusError)
+	if !ok || se.StatusCode != 404 {
+		t.Errorf("got %v, want a 404 StatusError", err)
+	}
+}
+
+func TestGetBoardID(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	if id, err := client.GetBoardID("Projects"); err != nil || id != 2 {
+		t.Errorf("got %d %v, want 2", id, err)
+	}
+	if _, err := client.GetBoardID("Sales"); err == nil {
+		t.Error("found a board that does not exist")
+	}
+}
+
+func TestGetMembers(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	members, err := client.GetMembers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, m := range members {
+		names = append(names, m.Identifier)
+	}
+	if got := strings.Join(names, ","); got != "jbloggs,asmith" {
+		t.Errorf("got active members %s, want jbloggs,asmith", got)
+	}
+
+	if name, err := client.GetMemberName("asmith"); err != nil || name != "Alex Smith" {
+		t.Errorf("got %q %v, want Alex Smith", name, err)
+	}
+	if _, err := client.GetMemberName("left"); err == nil {
+		t.Error("found the name of a disabled member")
+	}
+}
+
+func TestGetTeams(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	teams, err := client.GetTeams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 2 || teams[0].Name != "Helpdesk" || teams[1].Name != "Projects" {
+		t.Errorf("got %+v, want Helpdesk and Projects without the excluded board's team", teams)
+	}
+
+	members, err := client.GetTeamMembers(teams[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].ID != 10 {
+		t.Errorf("got %+v, want just the active member 10", members)
+	}
+}
+
+func TestTicketRecords(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	notes, err := client.GetTicketNotes(100)
+	if err != nil || len(notes) != 1 || notes[0].Text != "Printer offline" {
+		t.Errorf("notes = %+v %v", notes, err)
+	}
+	if notes, err := client.GetTicketNotes(101); err != nil || len(notes) != 0 {
+		t.Errorf("notes for another ticket = %+v %v", notes, err)
+	}
+
+	audit, err := client.GetTicketAuditTrail(100)
+	if err != nil || len(audit) != 1 {
+		t.Fatalf("audit = %+v %v", audit, err)
+	}
+	if from, to, ok := audit[0].StatusChange(); !ok || from != "New" || to != "In Progress" {
+		t.Errorf("StatusChange = %q %q %v", from, to, ok)
+	}
+
+	sources, err := client.GetTicketSources()
+	if err != nil || len(sources) != 2 {
+		t.Errorf("sources = %+v %v", sources, err)
+	}
+
+	priorities, err := client.GetSLAPriorities(3)
+	if err != nil || len(priorities) != 1 || priorities[0].ResolutionHours != 16 {
+		t.Errorf("SLA priorities = %+v %v", priorities, err)
+	}
+}
+
+func TestGetTimeEntries(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	s.TimeEntries = []psa.TimeEntry{
+		{ID: 1, Member: psa.Member{ID: 10}, TimeStart: now.AddDate(0, 0, -8), ActualHours: 1},
+		{ID: 2, Member: psa.Member{ID: 10}, TimeStart: now.AddDate(0, 0, -7), ActualHours: 2},
+		{ID: 3, Member: psa.Member{ID: 11}, TimeStart: now.AddDate(0, 0, -1), ActualHours: 3, BillableOption: "Billable"},
+		{ID: 4, Member: psa.Member{ID: 10}, TimeStart: now, ActualHours: 4},
+	}
+
+	entries, err := client.GetTimeEntries(now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].ID != 3 {
+		t.Errorf("got %+v, want entries 2 and 3, the range excluding its end", entries)
+	}
+	if !entries[1].IsBillable() || entries[0].IsBillable() {
+		t.Error("IsBillable does not follow BillableOption")
+	}
+
+	entries, err = client.GetTimeEntriesByMemberID(10, now.AddDate(0, 0, -30), now.AddDate(0, 0, 1), psa.TimeEntryFields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("got %d entries for member 10, want 3", len(entries))
+	}
+}
+
+func TestGetScheduleEntries(t *testing.T) {
+	s, client := newSystemServer(t)
+	defer s.Close()
+
+	service := psa.ScheduleType{ID: 4, Identifier: psa.ScheduleTypeServiceTicket}
+	activity := psa.ScheduleType{ID: 5, Identifier: "C"}
+	for i := 0; i < 150; i++ {
+		s.ScheduleEntries = append(s.ScheduleEntries, psa.ScheduleEntry{
+			ID: i + 1, ObjectID: 1000 + i, Type: service, Member: psa.Member{ID: 10 + i%2},
+			DateStart: now.AddDate(0, 0, i%14-7), Hours: 1,
+		})
+	}
+	s.ScheduleEntries = append(s.ScheduleEntries, psa.ScheduleEntry{
+		ID: 999, ObjectID: 1000, Type: activity, Member: psa.Member{ID: 10}, DateStart: now,
+	})
+
+	entries, err := client.GetScheduleEntries(now, now.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 0
+	for _, e := range s.ScheduleEntries {
+		if !e.DateStart.Before(now) && e.DateStart.Before(now.AddDate(0, 0, 7)) {
+			want++
+		}
+	}
+	if len(entries) != want {
+		t.Errorf("got %d entries in the coming week, want %d", len(entries), want)
+	}
+
+	entries, err = client.GetScheduleEntriesByMemberID(11, now.AddDate(0, 0, -7), now.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 75 {
+		t.Errorf("got %d entries for member 11, want 75", len(entries))
+	}
+
+	ids := []int{}
+	for i := 0; i < 130; i++ {
+		ids = append(ids, 1000+i)
+	}
+	requests := len(s.Requests)
+	entries, err = client.GetTicketScheduleEntries(ids, psa.ScheduleEntryFields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 130 {
+		t.Errorf("got %d ticket entries, want 130 without the activity", len(entries))
+	}
+	if n := len(s.Requests) - requests; n != 2 {
+		t.Errorf("made %d requests for 130 tickets, want 2", n)
+	}
+}
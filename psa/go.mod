@@ -1,7 +1,3 @@
-module scorecard/psa
+module github.com/simononebyte/scorecard/psa
 
 go 1.13
-
-require github.com/simononebyte/restup v0.0.0-20190904104733-4ccf492b5bdc
-
-replace github.com/simononebyte/restup => ../../restup
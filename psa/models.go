@@ -1,6 +1,10 @@
 package psa
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"time"
+)
 
 // Site ...
 type Site struct {
@@ -15,12 +19,102 @@ type Excludes struct {
 
 // Ticket ...
 type Ticket struct {
-	ID          int       `json:"id"`
-	DateEntered time.Time `json:"dateEntered"`
-	Company     Company   `json:"company"`
-	Board       Board     `json:"board"`
-	Summary     string    `josn:"summary"`
-	Info        Info      `json:"_info"`
+	ID           int           `json:"id"`
+	Summary      string        `json:"summary"`
+	RecordType   string        `json:"recordType"`
+	Board        Board         `json:"board"`
+	Status       Status        `json:"status"`
+	Company      Company       `json:"company"`
+	Contact      Contact       `json:"contact"`
+	Type         Reference     `json:"type"`
+	SubType      Reference     `json:"subType"`
+	Item         Reference     `json:"item"`
+	Team         Reference     `json:"team"`
+	Owner        Member        `json:"owner"`
+	Priority     Priority      `json:"priority"`
+	Source       Reference     `json:"source"`
+	Severity     string        `json:"severity"`
+	Impact       string        `json:"impact"`
+	Urgency      string        `json:"urgency"`
+	Resources    string        `json:"resources"`
+	RequiredDate time.Time     `json:"requiredDate"`
+	BudgetHours  float64       `json:"budgetHours"`
+	ActualHours  float64       `json:"actualHours"`
+	DateEntered  time.Time     `json:"dateEntered"`
+	EnteredBy    string        `json:"enteredBy"`
+	ClosedFlag   bool          `json:"closedFlag"`
+	ClosedDate   time.Time     `json:"closedDate"`
+	ClosedBy     string        `json:"closedBy"`
+	CustUpdated  bool          `json:"customerUpdatedFlag"`
+	SLA          Reference     `json:"sla"`
+	SLAStatus    string        `json:"slaStatus"`
+	IsInSLA      bool          `json:"isInSla"`
+	CustomFields []CustomField `json:"customFields"`
+	TicketSLA
+	Info Info `json:"_info"`
+}
+
+// TicketSLA holds the service level dates and targets for a ticket
+type TicketSLA struct {
+	DateResponded  time.Time `json:"dateResponded"`
+	RespondedBy    string    `json:"respondedBy"`
+	RespondMinutes int       `json:"respondMinutes"`
+	DateResplan    time.Time `json:"dateResplan"`
+	ResplanMinutes int       `json:"resPlanMinutes"`
+	DateResolved   time.Time `json:"dateResolved"`
+	ResolvedBy     string    `json:"resolvedBy"`
+	ResolveMinutes int       `json:"resolveMinutes"`
+}
+
+// SLAPriority holds an SLA's targets for tickets of one priority. The
+// targets are in business hours, as are the minutes on a ticket's TicketSLA.
+type SLAPriority struct {
+	ID              int       `json:"id"`
+	Priority        Reference `json:"priority"`
+	RespondHours    float64   `json:"respondHours"`
+	PlanWithin      float64   `json:"planWithin"`
+	ResolutionHours float64   `json:"resolutionHours"`
+}
+
+// CustomField is a user defined field. Value is nil when the field is unset,
+// otherwise a string, float64 or bool depending on the field type.
+type CustomField struct {
+	ID          int         `json:"id"`
+	Caption     string      `json:"caption"`
+	Type        string      `json:"type"`
+	EntryMethod string      `json:"entryMethod"`
+	Value       interface{} `json:"value"`
+}
+
+// CustomField returns the custom field with the given caption
+func (t Ticket) CustomField(caption string) (CustomField, bool) {
+	for _, f := range t.CustomFields {
+		if f.Caption == caption {
+			return f, true
+		}
+	}
+	return CustomField{}, false
+}
+
+// Reference is the id and name ConnectWise uses to link to another record
+type Reference struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Status ..
+type Status struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Sort int    `json:"sort"`
+}
+
+// Priority ..
+type Priority struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Sort  int    `json:"sort"`
+	Level string `json:"level"`
 }
 
 // Info ..
@@ -64,22 +158,28 @@ type TicketNote struct {
 	ID                    int       `json:"id"`
 	TicketID              int       `json:"ticketId"`
 	Text                  string    `json:"text"`
-	DetailDescriptionFlag string    `json:"DetailDescriptionFlag"`
-	InternalAnalysisFlag  string    `json:"InternalAnalysisFlag"`
-	ResolutionFlag        string    `json:"ResolutionFlag"`
-	Member                Member    `json:"Member"`
-	Contact               Contact   `json:"Contact"`
-	CustomerUpdatedFlag   string    `json:"CustomerUpdatedFlag"`
-	ProcessNotifications  string    `json:"ProcessNotifications"`
-	DateCreated           time.Time `json:"DateCreated"`
-	CreatedBy             string    `json:"CreatedBy"`
-	InternalFlag          string    `json:"InternalFlag"`
-	ExternalFlag          string    `json:"ExternalFlag"`
+	DetailDescriptionFlag bool      `json:"detailDescriptionFlag"`
+	InternalAnalysisFlag  bool      `json:"internalAnalysisFlag"`
+	ResolutionFlag        bool      `json:"resolutionFlag"`
+	Member                Member    `json:"member"`
+	Contact               Contact   `json:"contact"`
+	CustomerUpdatedFlag   bool      `json:"customerUpdatedFlag"`
+	ProcessNotifications  bool      `json:"processNotifications"`
+	DateCreated           time.Time `json:"dateCreated"`
+	CreatedBy             string    `json:"createdBy"`
+	InternalFlag          bool      `json:"internalFlag"`
+	ExternalFlag          bool      `json:"externalFlag"`
+}
+
+// IsMemberResponse reports whether the note was written by a member rather
+// than being the initial description or a customer's reply
+func (n TicketNote) IsMemberResponse() bool {
+	return n.Member.ID != 0 && !n.DetailDescriptionFlag
 }
 
 // Member ..
 type Member struct {
-	ID         string `json:"id"`
+	ID         int    `json:"id"`
 	Identifier string `json:"identifier"`
 	Name       string `json:"name"`
 	Info       Info   `json:"_info"`
@@ -87,7 +187,7 @@ type Member struct {
 
 // Contact ..
 type Contact struct {
-	ID   string `json:"id"`
+	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
@@ -111,9 +211,101 @@ type Audit struct {
 	AuditSource  string    `json:"auditSource"`
 }
 
-// Team ..
+var statusChangeText = regexp.MustCompile(`^Status has been updated from "(.*)" to "(.*)"\.?$`)
+
+// StatusChange returns the old and new status names if the entry records a
+// change of ticket status
+func (a Audit) StatusChange() (from string, to string, ok bool) {
+	m := statusChangeText.FindStringSubmatch(strings.TrimSpace(a.Text))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// StatusSince returns when the ticket entered its current status, the time
+// of the latest change to it in the audit trail, or when the ticket was
+// entered if its status has not changed
+func (t Ticket) StatusSince(audit []Audit) time.Time {
+	since := t.DateEntered
+	for _, a := range audit {
+		_, to, ok := a.StatusChange()
+		if ok && to == t.Status.Name && a.EnteredDate.After(since) {
+			since = a.EnteredDate
+		}
+	}
+	return since
+}
+
+// Team is a service team on a board. Members holds the member IDs.
 type Team struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Info Info   `json:"_info"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	BoardID    int    `json:"boardId"`
+	TeamLeader Member `json:"teamLeader"`
+	Members    []int  `json:"members"`
+	Info       Info   `json:"_info"`
+}
+
+// HasMember reports whether the member is on the team
+func (t Team) HasMember(memberID int) bool {
+	for _, id := range t.Members {
+		if id == memberID {
+			return true
+		}
+	}
+	return false
+}
+
+// Time entry charge types
+const (
+	ChargeToServiceTicket = "ServiceTicket"
+	ChargeToProjectTicket = "ProjectTicket"
+)
+
+// TimeEntry is time a member logged. ChargeToID is the ID of the ticket,
+// project or activity charged, given by ChargeToType.
+type TimeEntry struct {
+	ID             int       `json:"id"`
+	Company        Company   `json:"company"`
+	ChargeToID     int       `json:"chargeToId"`
+	ChargeToType   string    `json:"chargeToType"`
+	Member         Member    `json:"member"`
+	TimeStart      time.Time `json:"timeStart"`
+	TimeEnd        time.Time `json:"timeEnd"`
+	ActualHours    float64   `json:"actualHours"`
+	BillableOption string    `json:"billableOption"`
+	WorkType       Reference `json:"workType"`
+	Notes          string    `json:"notes"`
+	Info           Info      `json:"_info"`
+}
+
+// IsBillable reports whether the time is charged to the customer
+func (t TimeEntry) IsBillable() bool {
+	return t.BillableOption == "Billable"
+}
+
+// ScheduleTypeServiceTicket is the schedule entry type for service tickets
+const ScheduleTypeServiceTicket = "S"
+
+// ScheduleEntry is time a member is booked for. ObjectID is the ID of the
+// ticket or activity booked, given by the type identifier.
+type ScheduleEntry struct {
+	ID        int          `json:"id"`
+	ObjectID  int          `json:"objectId"`
+	Name      string       `json:"name"`
+	Member    Member       `json:"member"`
+	Type      ScheduleType `json:"type"`
+	Status    Reference    `json:"status"`
+	DateStart time.Time    `json:"dateStart"`
+	DateEnd   time.Time    `json:"dateEnd"`
+	Hours     float64      `json:"hours"`
+	DoneFlag  bool         `json:"doneFlag"`
+	Info      Info         `json:"_info"`
+}
+
+// ScheduleType ..
+type ScheduleType struct {
+	ID         int    `json:"id"`
+	Identifier string `json:"identifier"`
 }
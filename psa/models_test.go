@@ -0,0 +1,158 @@
+package psa
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readTicket(t *testing.T, name string) Ticket {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ticket Ticket
+	if err := json.Unmarshal(data, &ticket); err != nil {
+		t.Fatalf("decoding %s: %s", name, err)
+	}
+	return ticket
+}
+
+func date(s string) time.Time {
+	d, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestDecodeTicket(t *testing.T) {
+	tests := []struct {
+		file         string
+		id           int
+		status       string
+		priority     Priority
+		sla          Reference
+		isInSLA      bool
+		owner        Member
+		contactID    int
+		closed       bool
+		closedDate   time.Time
+		requiredDate time.Time
+		custUpdated  bool
+		respondMins  int
+		resolveMins  int
+		lastUpdated  time.Time
+	}{
+		{
+			file:         "ticket_closed.json",
+			id:           48211,
+			status:       ">Closed",
+			priority:     Priority{ID: 8, Name: "Priority 3 - Normal Response", Sort: 6, Level: "Medium"},
+			sla:          Reference{ID: 3, Name: "Standard"},
+			isInSLA:      true,
+			owner:        Member{ID: 177, Identifier: "jbloggs", Name: "Joe Bloggs"},
+			contactID:    8823,
+			closed:       true,
+			closedDate:   date("2020-03-03T11:40:07Z"),
+			requiredDate: date("2020-03-06T17:00:00Z"),
+			respondMins:  26,
+			resolveMins:  656,
+			lastUpdated:  date("2020-03-03T11:40:08Z"),
+		},
+		{
+			file:        "ticket_open.json",
+			id:          48390,
+			status:      "Waiting on Customer",
+			priority:    Priority{ID: 4, Name: "Priority 4 - Scheduled Maintenance", Sort: 8, Level: "Low"},
+			sla:         Reference{ID: 3, Name: "Standard"},
+			owner:       Member{ID: 204, Identifier: "asmith", Name: "Alex Smith"},
+			contactID:   9120,
+			custUpdated: true,
+			lastUpdated: date("2020-03-05T08:30:44Z"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ticket := readTicket(t, tt.file)
+
+			if ticket.ID != tt.id {
+				t.Errorf("ID = %d, want %d", ticket.ID, tt.id)
+			}
+			if ticket.Status.Name != tt.status {
+				t.Errorf("Status = %q, want %q", ticket.Status.Name, tt.status)
+			}
+			if ticket.Priority != tt.priority {
+				t.Errorf("Priority = %+v, want %+v", ticket.Priority, tt.priority)
+			}
+			if ticket.SLA != tt.sla || ticket.IsInSLA != tt.isInSLA {
+				t.Errorf("SLA = %+v in SLA %v, want %+v in SLA %v", ticket.SLA, ticket.IsInSLA, tt.sla, tt.isInSLA)
+			}
+			owner := ticket.Owner
+			owner.Info = Info{}
+			if owner != tt.owner {
+				t.Errorf("Owner = %+v, want %+v", owner, tt.owner)
+			}
+			if ticket.Contact.ID != tt.contactID {
+				t.Errorf("Contact ID = %d, want %d", ticket.Contact.ID, tt.contactID)
+			}
+			if ticket.ClosedFlag != tt.closed || !ticket.ClosedDate.Equal(tt.closedDate) {
+				t.Errorf("Closed = %v %s, want %v %s", ticket.ClosedFlag, ticket.ClosedDate, tt.closed, tt.closedDate)
+			}
+			if !ticket.RequiredDate.Equal(tt.requiredDate) {
+				t.Errorf("RequiredDate = %s, want %s", ticket.RequiredDate, tt.requiredDate)
+			}
+			if ticket.CustUpdated != tt.custUpdated {
+				t.Errorf("CustUpdated = %v, want %v", ticket.CustUpdated, tt.custUpdated)
+			}
+			if ticket.RespondMinutes != tt.respondMins || ticket.ResolveMinutes != tt.resolveMins {
+				t.Errorf("SLA minutes = %d/%d, want %d/%d", ticket.RespondMinutes, ticket.ResolveMinutes, tt.respondMins, tt.resolveMins)
+			}
+			if !ticket.Info.LastUpdated.Equal(tt.lastUpdated) {
+				t.Errorf("LastUpdated = %s, want %s", ticket.Info.LastUpdated, tt.lastUpdated)
+			}
+		})
+	}
+}
+
+func TestDecodeTicketNullRequiredDate(t *testing.T) {
+	ticket := readTicket(t, "ticket_open.json")
+	if !ticket.RequiredDate.IsZero() {
+		t.Errorf("RequiredDate = %s, want zero for null", ticket.RequiredDate)
+	}
+	if !ticket.ClosedDate.IsZero() {
+		t.Errorf("ClosedDate = %s, want zero when missing", ticket.ClosedDate)
+	}
+}
+
+func TestDecodeTicketCustomFields(t *testing.T) {
+	tests := []struct {
+		file    string
+		caption string
+		want    interface{}
+		found   bool
+	}{
+		{"ticket_closed.json", "Escalated", true, true},
+		{"ticket_closed.json", "Affected Users", float64(3), true},
+		{"ticket_closed.json", "RMM Alert ID", nil, true},
+		{"ticket_closed.json", "Missing", nil, false},
+		{"ticket_open.json", "Escalated", false, true},
+		{"ticket_open.json", "RMM Alert ID", "ALRT-55102", true},
+	}
+
+	for _, tt := range tests {
+		ticket := readTicket(t, tt.file)
+		f, found := ticket.CustomField(tt.caption)
+		if found != tt.found {
+			t.Errorf("%s %q found = %v, want %v", tt.file, tt.caption, found, tt.found)
+			continue
+		}
+		if f.Value != tt.want {
+			t.Errorf("%s %q = %#v, want %#v", tt.file, tt.caption, f.Value, tt.want)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package psatest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// condition is a parsed ConnectWise conditions string, evaluated against a
+// record decoded into a map
+type condition interface {
+	match(record map[string]interface{}) bool
+}
+
+type andCondition []condition
+
+func (c andCondition) match(r map[string]interface{}) bool {
+	for _, sub := range c {
+		if !sub.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type orCondition []condition
+
+func (c orCondition) match(r map[string]interface{}) bool {
+	for _, sub := range c {
+		if sub.match(r) {
+			return true
+		}
+	}
+	return false
+}
+
+type notCondition struct {
+	cond condition
+}
+
+func (c notCondition) match(r map[string]interface{}) bool {
+	return !c.cond.match(r)
+}
+
+// comparison is field op value, e.g. Board/ID = 5
+type comparison struct {
+	field string
+	op    string
+	value token
+	list  []token
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokDate
+	tokOp
+	tokOpen
+	tokClose
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// parseConditions parses the subset of the ConnectWise conditions syntax
+// used by the psa package: comparisons joined with AND, OR and NOT, grouped
+// with brackets. Values are numbers, 'strings' or "strings", [dates], True,
+// False and NULL. Operators are =, !=, <, <=, >, >=, LIKE, NOT LIKE, IN and
+// NOT IN. An empty string matches everything.
+func parseConditions(s string) (condition, error) {
+	if strings.TrimSpace(s) == "" {
+		return andCondition{}, nil
+	}
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q in conditions", p.peek().text)
+	}
+	return cond, nil
+}
+
+func lex(s string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokOpen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokClose, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ","})
+			i++
+		case c == '\'' || c == '"':
+			text, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, text})
+			i += n
+		case c == '[':
+			end := strings.IndexRune(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated date in conditions")
+			}
+			tokens = append(tokens, token{tokDate, s[i+1 : i+end]})
+			i += end + 1
+		case strings.ContainsRune("=!<>", c):
+			j := i + 1
+			for j < len(s) && strings.ContainsRune("=<>", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokOp, s[i:j]})
+			i = j
+		case c == '-' || unicode.IsDigit(c):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, s[i:j]})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) ||
+				s[j] == '_' || s[j] == '/') {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in conditions", c)
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+// lexString reads the quoted string at the start of s, where a backslash
+// escapes the character after it, returning its text and length
+func lexString(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] == s[0] {
+			return b.String(), i + 1, nil
+		}
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, fmt.Errorf("unterminated string in conditions")
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (condition, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orCondition{first}
+	for p.keyword("OR") {
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, next)
+	}
+	if len(or) == 1 {
+		return first, nil
+	}
+	return or, nil
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	first, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	and := andCondition{first}
+	for p.keyword("AND") {
+		next, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, next)
+	}
+	if len(and) == 1 {
+		return first, nil
+	}
+	return and, nil
+}
+
+func (p *parser) parseFactor() (condition, error) {
+	if p.keyword("NOT") {
+		cond, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{cond}, nil
+	}
+	if p.peek().kind == tokOpen {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokClose {
+			return nil, fmt.Errorf("missing ) in conditions")
+		}
+		return cond, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (condition, error) {
+	field := p.next()
+	if field.kind != tokIdent {
+		return nil, fmt.Errorf("expected field name, found %q", field.text)
+	}
+
+	c := comparison{field: field.text}
+	switch {
+	case p.peek().kind == tokOp:
+		c.op = p.next().text
+	case p.keyword("LIKE"):
+		c.op = "like"
+	case p.keyword("IN"):
+		c.op = "in"
+	case p.keyword("NOT"):
+		switch {
+		case p.keyword("LIKE"):
+			c.op = "not like"
+		case p.keyword("IN"):
+			c.op = "not in"
+		default:
+			return nil, fmt.Errorf("expected LIKE or IN after NOT")
+		}
+	default:
+		return nil, fmt.Errorf("expected operator after %s", field.text)
+	}
+
+	if c.op == "in" || c.op == "not in" {
+		if p.next().kind != tokOpen {
+			return nil, fmt.Errorf("expected ( after IN")
+		}
+		for {
+			c.list = append(c.list, p.next())
+			t := p.next()
+			if t.kind == tokClose {
+				break
+			}
+			if t.kind != tokComma {
+				return nil, fmt.Errorf("expected , or ) in IN list")
+			}
+		}
+		return c, nil
+	}
+
+	c.value = p.next()
+	switch c.value.kind {
+	case tokNumber, tokString, tokDate, tokIdent:
+	default:
+		return nil, fmt.Errorf("expected value after %s %s", c.field, c.op)
+	}
+	return c, nil
+}
+
+func (c comparison) match(r map[string]interface{}) bool {
+	v, found := lookup(r, c.field)
+
+	switch c.op {
+	case "in", "not in":
+		in := false
+		for _, t := range c.list {
+			if compare(v, found, t) == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (c.op == "in")
+	case "like", "not like":
+		s, _ := v.(string)
+		return like(s, c.value.text) == (c.op == "like")
+	}
+
+	if c.value.kind == tokIdent && strings.EqualFold(c.value.text, "NULL") {
+		null := isNull(v, found)
+		if i := strings.LastIndex(c.field, "/"); i >= 0 && strings.EqualFold(c.field[i+1:], "id") {
+			// A reference/id is NULL when the reference itself is unset
+			null = isNull(lookup(r, c.field[:i]))
+		}
+		if c.op == "!=" || c.op == "<>" {
+			return !null
+		}
+		return null
+	}
+
+	cmp := compare(v, found, c.value)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=", "<>":
+		return cmp != 0
+	case "<":
+		return cmp == -1
+	case "<=":
+		return cmp == -1 || cmp == 0
+	case ">":
+		return cmp == 1
+	case ">=":
+		return cmp == 1 || cmp == 0
+	}
+	return false
+}
+
+// compare returns -1, 0 or 1 comparing the record value with the token, or
+// 2 when they cannot be compared
+func compare(v interface{}, found bool, t token) int {
+	if !found || v == nil {
+		return 2
+	}
+
+	switch t.kind {
+	case tokNumber:
+		want, err := strconv.ParseFloat(t.text, 64)
+		got, ok := v.(float64)
+		if err != nil || !ok {
+			return 2
+		}
+		return cmpFloat(got, want)
+	case tokDate:
+		want, err := parseDate(t.text)
+		s, ok := v.(string)
+		if err != nil || !ok {
+			return 2
+		}
+		got, err := parseDate(s)
+		if err != nil {
+			return 2
+		}
+		return cmpFloat(float64(got.UnixNano()), float64(want.UnixNano()))
+	case tokIdent:
+		if b, ok := v.(bool); ok {
+			want, err := strconv.ParseBool(strings.ToLower(t.text))
+			if err != nil {
+				return 2
+			}
+			if b == want {
+				return 0
+			}
+			return 1
+		}
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		if f, isNum := v.(float64); isNum {
+			s = strconv.FormatFloat(f, 'f', -1, 64)
+		} else {
+			return 2
+		}
+	}
+	return strings.Compare(strings.ToLower(s), strings.ToLower(t.text))
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-1-2T15:04:05Z", "2006-1-2"}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// isNull treats missing, empty and zero date values as NULL, as they are how
+// unset fields of the psa models encode
+func isNull(v interface{}, found bool) bool {
+	if !found || v == nil {
+		return true
+	}
+	switch x := v.(type) {
+	case string:
+		return x == "" || strings.HasPrefix(x, "0001-01-01")
+	case map[string]interface{}:
+		id, _ := x["id"].(float64)
+		return id == 0
+	}
+	return false
+}
+
+// like matches s against a pattern where * and % match any run of characters
+func like(s string, pattern string) bool {
+	s = strings.ToLower(s)
+	pattern = strings.ToLower(strings.Replace(pattern, "%", "*", -1))
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return s == pattern
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if last == "" {
+		// A lone * only matches non empty values
+		return pattern != "*" || s != ""
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// lookup finds a field by its slash separated path, ignoring case
+func lookup(r map[string]interface{}, path string) (interface{}, bool) {
+	var v interface{} = r
+	for _, key := range strings.Split(path, "/") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = getFold(m, key)
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func getFold(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
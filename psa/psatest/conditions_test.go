@@ -0,0 +1,171 @@
+package psatest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// record decodes a JSON ticket the way filter sees it
+func record(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	r := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+const testTicket = `{
+	"id": 1500,
+	"summary": "Printer 'Reception' offline",
+	"site": {"id": 4, "name": "Main \\ \"HQ\""},
+	"resources": "jbloggs, asmith",
+	"closedFlag": false,
+	"budgetHours": 1.5,
+	"board": {"id": 25, "name": "Reactive Support"},
+	"status": {"id": 538, "name": "Waiting on Customer"},
+	"owner": {"id": 0, "name": ""},
+	"dateEntered": "2020-03-04T14:02:31Z",
+	"closedDate": "0001-01-01T00:00:00Z",
+	"_info": {"lastUpdated": "2020-03-05T08:30:44Z"}
+}`
+
+func TestConditionsMatch(t *testing.T) {
+	r := record(t, testTicket)
+
+	tests := []struct {
+		conditions string
+		want       bool
+	}{
+		// Empty matches everything
+		{"", true},
+		{"   ", true},
+
+		// Numbers and nested fields, ignoring case
+		{"id = 1500", true},
+		{"id = 150", false},
+		{"Board/ID = 25", true},
+		{"board/id != 25", false},
+		{"board/id <> 26", true},
+		{"budgetHours > 1", true},
+		{"budgetHours <= 1.5", true},
+		{"budgetHours < 1.5", false},
+		{"id >= -1", true},
+
+		// Quoting
+		{"board/name = 'Reactive Support'", true},
+		{`board/name = "Reactive Support"`, true},
+		{"board/name = 'reactive support'", true},
+		{"board/name = 'Reactive'", false},
+		{`summary = "Printer 'Reception' offline"`, true},
+		{"status/name = 'Waiting on Customer' AND id = 1500", true},
+
+		// Backslash escapes
+		{`summary = 'Printer \'Reception\' offline'`, true},
+		{`summary = "Printer \'Reception\' offline"`, true},
+		{`site/name = "Main \\ \"HQ\""`, true},
+		{`site/name = "Main \ \"HQ\""`, false},
+		{`site/name LIKE "*\"HQ\""`, true},
+
+		// Booleans
+		{"closedFlag = False", true},
+		{"ClosedFlag = true", false},
+		{"closedFlag != True", true},
+
+		// NULL, including zero dates and references
+		{"resources = NULL", false},
+		{"resources != NULL", true},
+		{"owner = NULL", true},
+		{"owner/id = NULL", true},
+		{"board/id != NULL", true},
+		{"closedDate = NULL", true},
+		{"missingField = NULL", true},
+		{"dateEntered != null", true},
+
+		// Dates, with and without a time
+		{"dateEntered >= [2020-03-04]", true},
+		{"dateEntered >= [2020-3-5]", false},
+		{"dateEntered < [2020-03-04T14:02:32Z]", true},
+		{"dateEntered <= [2020-03-04T14:02:30Z]", false},
+		{"_info/lastUpdated > [2020-03-05T08:00:00Z]", true},
+		{"_info/LastUpdated <= [2020-3-5T08:00:00Z]", false},
+
+		// LIKE
+		{"resources LIKE '*'", true},
+		{"resources LIKE '*asmith'", true},
+		{"resources LIKE 'JBLOGGS*'", true},
+		{"resources LIKE '%smi%'", true},
+		{"resources LIKE 'asmith*'", false},
+		{"resources NOT LIKE '*asmith'", false},
+		{"summary LIKE 'printer*offline'", true},
+		{"missingField LIKE '*'", false},
+
+		// IN
+		{"id IN (1, 1500, 2)", true},
+		{"id IN (1,2)", false},
+		{"id NOT IN (1,2)", true},
+		{"status/name IN ('New', 'Waiting on Customer')", true},
+		{"status/name NOT IN ('New', 'Waiting on Customer')", false},
+
+		// AND, OR, NOT and brackets
+		{"id = 1500 AND board/id = 25", true},
+		{"id = 1500 and board/id = 26", false},
+		{"id = 1 OR board/id = 25", true},
+		{"id = 1 or board/id = 26", false},
+		{"id = 1 OR id = 2 AND board/id = 25", false},
+		{"id = 1500 OR id = 2 AND board/id = 26", true},
+		{"(id = 1500 OR id = 2) AND board/id = 26", false},
+		{"((id = 1500) AND (Board/ID != 3)) AND Board/ID != 4", true},
+		{"NOT id = 1500", false},
+		{"NOT (id = 1 OR id = 2)", true},
+	}
+
+	for _, tt := range tests {
+		cond, err := parseConditions(tt.conditions)
+		if err != nil {
+			t.Errorf("%q: %s", tt.conditions, err)
+			continue
+		}
+		if got := cond.match(r); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.conditions, got, tt.want)
+		}
+	}
+}
+
+func TestConditionsErrors(t *testing.T) {
+	tests := []string{
+		"id =",
+		"id 1500",
+		"= 1500",
+		"summary = 'unterminated",
+		`summary = 'escaped end\'`,
+		`summary = "trailing \`,
+		"dateEntered > [2020-03-04",
+		"(id = 1",
+		"id = 1)",
+		"id IN 1, 2",
+		"id IN (1 2)",
+		"id NOT = 1",
+		"id = 1 AND",
+		"id = 1 ; id = 2",
+	}
+
+	for _, conditions := range tests {
+		if _, err := parseConditions(conditions); err == nil {
+			t.Errorf("%q parsed, want an error", conditions)
+		}
+	}
+}
+
+func TestConditionsInvalidDateNeverMatches(t *testing.T) {
+	r := record(t, testTicket)
+	for _, conditions := range []string{"dateEntered > [yesterday]", "dateEntered <= [yesterday]"} {
+		cond, err := parseConditions(conditions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cond.match(r) {
+			t.Errorf("%q matched", conditions)
+		}
+	}
+}
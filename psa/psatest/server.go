@@ -0,0 +1,313 @@
+// Package psatest provides an in-process fake of the ConnectWise REST API
+// for testing code that uses the psa package without network access.
+package psatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+const (
+	defaultPageSize = 25
+	maxPageSize     = 1000
+)
+
+// Server is a fake ConnectWise API serving the records it holds. Lists are
+// filtered by the conditions parameter, paged with page and pageSize and
+// trimmed to the fields parameter, as ConnectWise does.
+//
+// Records may be changed between requests by holding the lock.
+type Server struct {
+	sync.Mutex
+
+	Boards  []psa.Board
+	Tickets []psa.Ticket
+	Members []psa.Member
+	Sources []psa.TicketSource
+	Audit   map[int][]psa.Audit
+	Notes   map[int][]psa.TicketNote
+	Teams   []psa.Team
+
+	// DisabledMembers are the IDs of members served with disableOnlineFlag
+	// set, and UntypedMembers those served without a member type, as API
+	// members are. Other members are served as enabled technicians.
+	DisabledMembers []int
+	UntypedMembers  []int
+
+	// TimeEntries is the time logged by all members
+	TimeEntries []psa.TimeEntry
+
+	// ScheduleEntries is the time booked for all members
+	ScheduleEntries []psa.ScheduleEntry
+
+	// SLAPriorities holds the targets for each priority, keyed by SLA ID
+	SLAPriorities map[int][]psa.SLAPriority
+
+	// Requests records the method and URL of each request received
+	Requests []string
+
+	server *httptest.Server
+}
+
+// NewServer starts a Server, which must be closed when finished with
+func NewServer() *Server {
+	s := &Server{
+		Audit:         make(map[int][]psa.Audit),
+		Notes:         make(map[int][]psa.TicketNote),
+		SLAPriorities: make(map[int][]psa.SLAPriority),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL is the API base of the server
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a psa.Config for a client of the server
+func (s *Server) Config() psa.Config {
+	return psa.Config{
+		Company:  "fake",
+		Username: "public",
+		Password: "private",
+		ClientID: "psatest",
+		APIBase:  s.URL(),
+	}
+}
+
+var (
+	countPath = regexp.MustCompile(`^(.*)/count$`)
+	// itemPath matches the records belonging to another, e.g. a ticket's notes
+	itemPath = regexp.MustCompile(`^(/service/\w+)/(\d+)/(\w+)$`)
+)
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.RequestURI())
+
+	query := r.URL.Query()
+	conditions := query.Get("conditions")
+	if r.Method == http.MethodPost {
+		body := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid body: %s", err)
+			return
+		}
+		conditions = body["conditions"]
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	path = strings.TrimSuffix(path, "/search")
+
+	count := false
+	if m := countPath.FindStringSubmatch(path); m != nil {
+		count = true
+		path = m[1]
+	}
+
+	itemID := 0
+	if m := itemPath.FindStringSubmatch(path); m != nil {
+		itemID, _ = strconv.Atoi(m[2])
+		path = m[1] + "/{id}/" + m[3]
+	}
+
+	var records interface{}
+	switch path {
+	case "/service/tickets/{id}/notes":
+		records = s.Notes[itemID]
+	case "/service/boards/{id}/teams":
+		teams := []psa.Team{}
+		for _, t := range s.Teams {
+			if t.BoardID == itemID {
+				teams = append(teams, t)
+			}
+		}
+		records = teams
+	case "/service/SLAs/{id}/priorities":
+		records = s.SLAPriorities[itemID]
+	case "/service/boards":
+		records = s.Boards
+	case "/service/tickets":
+		records = s.Tickets
+	case "/service/sources":
+		records = s.Sources
+	case "/schedule/entries":
+		records = s.ScheduleEntries
+	case "/time/entries":
+		records = s.TimeEntries
+	case "/system/members":
+		records = s.members()
+	case "/system/audittrail":
+		id, _ := strconv.Atoi(query.Get("id"))
+		records = s.Audit[id]
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", r.URL.Path)
+		return
+	}
+
+	list, err := filter(records, conditions)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	if count {
+		writeJSON(w, map[string]int{"count": len(list)})
+		return
+	}
+
+	list, err = paginate(list, query.Get("page"), query.Get("pageSize"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if f := query.Get("fields"); f != "" {
+		for i, rec := range list {
+			list[i] = project(rec, strings.Split(f, ","))
+		}
+	}
+	writeJSON(w, list)
+}
+
+// members returns the members with the type and disableOnlineFlag fields
+// ConnectWise includes on the members list
+func (s *Server) members() []map[string]interface{} {
+	list := []map[string]interface{}{}
+	for _, m := range s.Members {
+		rec := map[string]interface{}{
+			"id":                m.ID,
+			"identifier":        m.Identifier,
+			"name":              m.Name,
+			"_info":             m.Info,
+			"type":              map[string]interface{}{"id": 1, "name": "Technician"},
+			"disableOnlineFlag": hasID(s.DisabledMembers, m.ID),
+		}
+		if hasID(s.UntypedMembers, m.ID) {
+			delete(rec, "type")
+		}
+		list = append(list, rec)
+	}
+	return list
+}
+
+func hasID(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// filter converts the records to maps and keeps those matching conditions
+func filter(records interface{}, conditions string) ([]map[string]interface{}, error) {
+	cond, err := parseConditions(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	all := []map[string]interface{}{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	list := []map[string]interface{}{}
+	for _, rec := range all {
+		if cond.match(rec) {
+			list = append(list, rec)
+		}
+	}
+	return list, nil
+}
+
+func paginate(list []map[string]interface{}, pageParam, sizeParam string) ([]map[string]interface{}, error) {
+	page, size := 1, defaultPageSize
+	var err error
+	if pageParam != "" {
+		if page, err = strconv.Atoi(pageParam); err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid page %q", pageParam)
+		}
+	}
+	if sizeParam != "" {
+		if size, err = strconv.Atoi(sizeParam); err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid pageSize %q", sizeParam)
+		}
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	start := (page - 1) * size
+	if start >= len(list) {
+		return []map[string]interface{}{}, nil
+	}
+	end := start + size
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[start:end], nil
+}
+
+// project keeps only the listed fields, which may be slash separated paths
+func project(rec map[string]interface{}, fields []string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, f := range fields {
+		keys := strings.Split(strings.TrimSpace(f), "/")
+		src, dst := rec, out
+		for i, key := range keys {
+			v, ok := getFold(src, key)
+			if !ok {
+				break
+			}
+			if i == len(keys)-1 {
+				dst[key] = v
+				break
+			}
+			next, ok := v.(map[string]interface{})
+			if !ok {
+				break
+			}
+			child, ok := dst[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				dst[key] = child
+			}
+			src, dst = next, child
+		}
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, a ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"code":    http.StatusText(status),
+		"message": fmt.Sprintf(format, a...),
+	})
+}
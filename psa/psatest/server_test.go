@@ -0,0 +1,189 @@
+package psatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.Boards = []psa.Board{{ID: 1, Name: "Reactive"}, {ID: 2, Name: "Projects"}}
+	for i := 1; i <= 60; i++ {
+		s.Tickets = append(s.Tickets, psa.Ticket{
+			ID:         i,
+			Summary:    "Ticket",
+			Board:      s.Boards[i%2],
+			ClosedFlag: i%3 == 0,
+		})
+	}
+	s.Notes[7] = []psa.TicketNote{{ID: 70, TicketID: 7, Text: "First"}}
+	s.Audit[7] = []psa.Audit{{Text: "Ticket created"}}
+	s.Teams = []psa.Team{{ID: 5, Name: "Helpdesk", BoardID: 1}, {ID: 6, Name: "Projects", BoardID: 2}}
+	s.SLAPriorities[3] = []psa.SLAPriority{{ID: 1, RespondHours: 2}}
+	return s
+}
+
+// get sends a GET request and decodes the JSON response into v, returning
+// the status code
+func get(t *testing.T, s *Server, path string, v interface{}) int {
+	t.Helper()
+	resp, err := http.Get(s.URL() + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServerFiltersAndCounts(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	conditions := url.QueryEscape("Board/ID = 1 AND ClosedFlag = False")
+	tickets := []psa.Ticket{}
+	get(t, s, "/service/tickets?pageSize=1000&conditions="+conditions, &tickets)
+	count := map[string]int{}
+	get(t, s, "/service/tickets/count?conditions="+conditions, &count)
+
+	want := 0
+	for _, ticket := range s.Tickets {
+		if ticket.Board.ID == 1 && !ticket.ClosedFlag {
+			want++
+		}
+	}
+	if len(tickets) != want || count["count"] != want {
+		t.Errorf("got %d tickets and count %d, want %d", len(tickets), count["count"], want)
+	}
+	for _, ticket := range tickets {
+		if ticket.Board.ID != 1 || ticket.ClosedFlag {
+			t.Errorf("ticket %d does not match the conditions", ticket.ID)
+		}
+	}
+}
+
+func TestServerPaging(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	tests := []struct {
+		query string
+		first int
+		n     int
+	}{
+		{"", 1, 25},
+		{"?page=2", 26, 25},
+		{"?page=3", 51, 10},
+		{"?page=4", 0, 0},
+		{"?pageSize=40&page=2", 41, 20},
+		{"?pageSize=5000", 1, 60},
+	}
+	for _, tt := range tests {
+		tickets := []psa.Ticket{}
+		get(t, s, "/service/tickets"+tt.query, &tickets)
+		if len(tickets) != tt.n {
+			t.Errorf("%q returned %d tickets, want %d", tt.query, len(tickets), tt.n)
+			continue
+		}
+		if tt.n > 0 && tickets[0].ID != tt.first {
+			t.Errorf("%q started at ticket %d, want %d", tt.query, tickets[0].ID, tt.first)
+		}
+	}
+
+	for _, query := range []string{"?page=0", "?page=x", "?pageSize=-1"} {
+		if status := get(t, s, "/service/tickets"+query, nil); status != http.StatusBadRequest {
+			t.Errorf("%q returned %d, want 400", query, status)
+		}
+	}
+}
+
+func TestServerFields(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	list := []map[string]interface{}{}
+	get(t, s, "/service/tickets?pageSize=1&fields="+url.QueryEscape("id,board/name"), &list)
+	if len(list) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(list))
+	}
+	want := `{"board":{"name":"Projects"},"id":1}`
+	if got, _ := json.Marshal(list[0]); string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	body, _ := json.Marshal(map[string]string{"conditions": "id IN (4, 8, 99)"})
+	resp, err := http.Post(s.URL()+"/service/tickets/search?pageSize=1000", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	tickets := []psa.Ticket{}
+	if err := json.NewDecoder(resp.Body).Decode(&tickets); err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 2 || tickets[0].ID != 4 || tickets[1].ID != 8 {
+		t.Errorf("got %+v, want tickets 4 and 8", tickets)
+	}
+}
+
+func TestServerRoutes(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	tests := []struct {
+		path string
+		n    int
+	}{
+		{"/service/boards", 2},
+		{"/service/boards/1/teams", 1},
+		{"/service/boards/3/teams", 0},
+		{"/service/tickets/7/notes", 1},
+		{"/service/tickets/8/notes", 0},
+		{"/service/SLAs/3/priorities", 1},
+		{"/system/audittrail?type=Ticket&id=7", 1},
+		{"/system/audittrail?type=Ticket&id=8", 0},
+		{"/system/members", 0},
+		{"/time/entries", 0},
+		{"/schedule/entries", 0},
+	}
+	for _, tt := range tests {
+		list := []interface{}{}
+		if status := get(t, s, tt.path, &list); status != http.StatusOK {
+			t.Errorf("%s returned %d", tt.path, status)
+		}
+		if len(list) != tt.n {
+			t.Errorf("%s returned %d records, want %d", tt.path, len(list), tt.n)
+		}
+	}
+
+	if status := get(t, s, "/company/companies", nil); status != http.StatusNotFound {
+		t.Errorf("unknown route returned %d, want 404", status)
+	}
+	if status := get(t, s, "/service/tickets?conditions="+url.QueryEscape("id = "), nil); status != http.StatusBadRequest {
+		t.Errorf("bad conditions returned %d, want 400", status)
+	}
+}
+
+func TestServerRecordsRequests(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	get(t, s, "/service/boards?pageSize=10", nil)
+	if len(s.Requests) != 1 || s.Requests[0] != "GET /service/boards?pageSize=10" {
+		t.Errorf("Requests = %q", s.Requests)
+	}
+}
@@ -0,0 +1,258 @@
+package psa
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const recordingFile = "recording.json"
+
+// recording describes a directory of recorded API traffic
+type recording struct {
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// exchange is one recorded request and its response
+type exchange struct {
+	Method   string          `json:"method"`
+	Cmd      string          `json:"cmd"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Status   int             `json:"status,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (e exchange) key() string {
+	return e.Method + " " + e.Cmd + " " + string(e.Body)
+}
+
+// Recorder saves every request and response passing through the transports
+// it wraps, one file of JSON lines per transport name. Only the method, path
+// and body are saved, never the headers, so no credentials are recorded.
+type Recorder struct {
+	mu    sync.Mutex
+	dir   string
+	files map[string]*os.File
+}
+
+// NewRecorder creates dir and records the time the recording was made
+func NewRecorder(dir string, recordedAt time.Time) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(recording{RecordedAt: recordedAt}, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, recordingFile), data, 0644); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, files: make(map[string]*os.File)}, nil
+}
+
+// Wrap returns a Transport that records the traffic through t as name
+func (r *Recorder) Wrap(name string, t Transport) Transport {
+	return &recordingTransport{recorder: r, name: name, next: t}
+}
+
+// Close closes the recording files
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for _, f := range r.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	r.files = map[string]*os.File{}
+	return firstErr
+}
+
+func (r *Recorder) write(name string, e exchange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.files[name]
+	if !ok {
+		var err error
+		f, err = os.Create(filepath.Join(r.dir, name+".jsonl"))
+		if err != nil {
+			return err
+		}
+		r.files[name] = f
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	name     string
+	next     Transport
+}
+
+func (t *recordingTransport) Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) error {
+	e := exchange{Method: method, Cmd: cmd}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		e.Body = data
+	}
+
+	var raw json.RawMessage
+	err := t.next.Do(ctx, method, cmd, body, &raw)
+	if err != nil {
+		// Cancelled requests say nothing about the API, so are not recorded
+		if ctx.Err() != nil {
+			return err
+		}
+		e.Error = err.Error()
+		if se, ok := err.(*StatusError); ok {
+			e.Status = se.StatusCode
+		}
+	} else {
+		e.Response = raw
+	}
+
+	if werr := t.recorder.write(t.name, e); werr != nil {
+		return fmt.Errorf("error recording %s %s: %s", method, cmd, werr)
+	}
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Replayer serves recorded traffic back in place of the API. Repeated
+// requests get the recorded responses in order, the last repeating once they
+// run out.
+type Replayer struct {
+	recordedAt time.Time
+	mu         sync.Mutex
+	exchanges  map[string]map[string][]exchange
+}
+
+// NewReplayer loads a directory written by a Recorder
+func NewReplayer(dir string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, recordingFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading recording: %s", err)
+	}
+	rec := recording{}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("error reading recording: %s", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replayer{recordedAt: rec.RecordedAt, exchanges: map[string]map[string][]exchange{}}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".jsonl")
+		if err := r.load(name, file); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Replayer) load(name string, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	byKey := map[string][]exchange{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 256*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		e := exchange{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("error reading %s line %d: %s", file, line, err)
+		}
+		byKey[e.key()] = append(byKey[e.key()], e)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	r.exchanges[name] = byKey
+	return nil
+}
+
+// RecordedAt is when the recording was made, queries relative to the
+// current time should be pinned to it when replaying
+func (r *Replayer) RecordedAt() time.Time {
+	return r.recordedAt
+}
+
+// Transport returns a Transport serving the traffic recorded as name
+func (r *Replayer) Transport(name string) Transport {
+	return &replayTransport{replayer: r, name: name}
+}
+
+type replayTransport struct {
+	replayer *Replayer
+	name     string
+}
+
+func (t *replayTransport) Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) error {
+	e := exchange{Method: method, Cmd: cmd}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		e.Body = data
+	}
+
+	r := t.replayer
+	r.mu.Lock()
+	queue := r.exchanges[t.name][e.key()]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("no recorded %s response for %s %s", t.name, method, cmd)
+	}
+	e = queue[0]
+	if len(queue) > 1 {
+		r.exchanges[t.name][e.key()] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	if e.Error != "" {
+		if e.Status != 0 {
+			return &StatusError{
+				StatusCode: e.Status,
+				Status:     fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+			}
+		}
+		return fmt.Errorf("%s", e.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(e.Response, v)
+}
@@ -0,0 +1,207 @@
+package psa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// Default retry settings, used for any RetryPolicy field left at zero
+const (
+	DefaultMaxAttempts    = 4
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultRequestTimeout = time.Minute
+	DefaultDeadline       = 5 * time.Minute
+)
+
+// RetryPolicy controls how failed API requests are retried. Requests that
+// fail with a 429, a 5xx, a timeout or a truncated response are retried with exponential backoff
+// and jitter, waiting at least as long as any Retry-After the server sent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubling each time
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+	// RequestTimeout bounds each attempt
+	RequestTimeout time.Duration
+	// Deadline bounds all attempts of a request, including the waits
+	Deadline time.Duration
+	// Logf reports each retry, fmt.Printf is used when nil
+	Logf func(format string, a ...interface{})
+}
+
+// StatusError is an HTTP error response
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected response: %s", e.Status)
+	}
+	return fmt.Sprintf("unexpected response: %s: %s", e.Status, e.Message)
+}
+
+var errRequestTimeout = errors.New("request timed out")
+
+// decodeError is a response body that could not be decoded, keeping the
+// decoder's error so a truncated body can be retried
+type decodeError struct {
+	method string
+	cmd    string
+	err    error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("error decoding %s %s: %s", e.method, e.cmd, e.err)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.RequestTimeout <= 0 {
+		p.RequestTimeout = DefaultRequestTimeout
+	}
+	if p.Deadline <= 0 {
+		p.Deadline = DefaultDeadline
+	}
+	if p.Logf == nil {
+		p.Logf = func(format string, a ...interface{}) {
+			fmt.Printf(format+"\n", a...)
+		}
+	}
+	return p
+}
+
+// Do calls fn, retrying as the policy allows, and decodes the result into v.
+// Each attempt decodes into a fresh value of v's type which is only copied to
+// v on success, so an attempt abandoned on timeout can never write to v.
+// name identifies the request in the retry log. fn is passed a context that
+// is cancelled when the attempt times out or ctx is done.
+func (p RetryPolicy) Do(ctx context.Context, name string, v interface{},
+	fn func(ctx context.Context, v interface{}) error) error {
+
+	p = p.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, p.Deadline)
+	defer cancel()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = p.attempt(ctx, v, fn)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.MaxAttempts || !isRetryable(err) {
+			break
+		}
+
+		wait := p.backoff(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			break
+		}
+		p.Logf("retrying %s in %s (attempt %d of %d): %s",
+			name, wait.Round(time.Millisecond), attempt+1, p.MaxAttempts, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+func (p RetryPolicy) attempt(ctx context.Context, v interface{},
+	fn func(ctx context.Context, v interface{}) error) error {
+
+	attemptCtx, cancel := context.WithTimeout(ctx, p.RequestTimeout)
+	defer cancel()
+
+	dst := reflect.New(reflect.TypeOf(v).Elem())
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(attemptCtx, dst.Interface())
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if attemptCtx.Err() == context.DeadlineExceeded {
+				return errRequestTimeout
+			}
+			return err
+		}
+		reflect.ValueOf(v).Elem().Set(dst.Elem())
+		return nil
+	case <-attemptCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errRequestTimeout
+	}
+}
+
+// backoff returns the wait before the next attempt, a random duration
+// between half and all of the exponential backoff, or the server's
+// Retry-After if that is longer
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	wait := p.InitialBackoff << uint(attempt-1)
+	if wait > p.MaxBackoff || wait <= 0 {
+		wait = p.MaxBackoff
+	}
+	wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+
+	if se, ok := err.(*StatusError); ok && se.RetryAfter > wait {
+		wait = se.RetryAfter
+	}
+	return wait
+}
+
+// isRetryable reports whether err is transient: a 429 or 5xx response, a
+// network timeout, a response cut short or the attempt timing out. Errors
+// from the request's own context are never retried.
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case *StatusError:
+		return e.StatusCode == 429 || e.StatusCode >= 500
+	case *url.Error:
+		err = e.Err
+	case *decodeError:
+		err = e.err
+	}
+
+	switch err {
+	case errRequestTimeout, io.ErrUnexpectedEOF:
+		return true
+	case context.Canceled, context.DeadlineExceeded:
+		return false
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		return true
+	}
+	return false
+}
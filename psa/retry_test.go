@@ -0,0 +1,175 @@
+package psa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubTransport answers each request with the next of its errors, and with
+// body once they run out
+type stubTransport struct {
+	mu    sync.Mutex
+	errs  []error
+	body  string
+	calls []time.Time
+}
+
+func (t *stubTransport) Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, time.Now())
+	if len(t.calls) <= len(t.errs) {
+		return t.errs[len(t.calls)-1]
+	}
+	return json.Unmarshal([]byte(t.body), v)
+}
+
+// stubClient returns a client whose requests go through t, retrying quickly
+func stubClient(t *testing.T, transport *stubTransport, p RetryPolicy) *Client {
+	t.Helper()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = time.Millisecond
+	p.Logf = func(string, ...interface{}) {}
+	client, err := NewClient(Config{Transport: transport, Retry: p}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"429", &StatusError{StatusCode: 429}, true},
+		{"500", &StatusError{StatusCode: 500}, true},
+		{"503", &StatusError{StatusCode: 503}, true},
+		{"400", &StatusError{StatusCode: 400}, false},
+		{"401", &StatusError{StatusCode: 401}, false},
+		{"404 mentioning 500", &StatusError{StatusCode: 404, Message: "board 500 not found"}, false},
+		{"attempt timeout", errRequestTimeout, true},
+		{"network timeout", &url.Error{Op: "Get", URL: "x", Err: timeoutError{}}, true},
+		{"dial timeout", &net.OpError{Op: "dial", Err: timeoutError{}}, true},
+		{"truncated body", &decodeError{"GET", "x", io.ErrUnexpectedEOF}, true},
+		{"truncated response", &url.Error{Op: "Get", URL: "x", Err: io.ErrUnexpectedEOF}, true},
+		{"bad json", &decodeError{"GET", "x", errors.New("invalid character")}, false},
+		{"cancelled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"text only", errors.New("503 service unavailable: timeout"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt  int
+		err      error
+		min, max time.Duration
+	}{
+		{1, errRequestTimeout, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, errRequestTimeout, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, errRequestTimeout, 200 * time.Millisecond, 400 * time.Millisecond},
+		{5, errRequestTimeout, 500 * time.Millisecond, time.Second},
+		{64, errRequestTimeout, 500 * time.Millisecond, time.Second},
+		{1, &StatusError{StatusCode: 429, RetryAfter: 3 * time.Second}, 3 * time.Second, 3 * time.Second},
+		{5, &StatusError{StatusCode: 503, RetryAfter: time.Millisecond}, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			if wait := p.backoff(tt.attempt, tt.err); wait < tt.min || wait > tt.max {
+				t.Errorf("attempt %d: waited %s, want %s to %s", tt.attempt, wait, tt.min, tt.max)
+				break
+			}
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		errs        []error
+		wantCalls   int
+		wantErr     bool
+	}{
+		{"first time", 4, nil, 1, false},
+		{"after transient errors", 4, []error{&StatusError{StatusCode: 502}, errRequestTimeout}, 3, false},
+		{"max attempts", 3, []error{&StatusError{StatusCode: 500}, &StatusError{StatusCode: 500}, &StatusError{StatusCode: 500}}, 3, true},
+		{"single attempt", 1, []error{&StatusError{StatusCode: 500}}, 1, true},
+		{"not retryable", 4, []error{&StatusError{StatusCode: 403}}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &stubTransport{errs: tt.errs, body: `[{"id": 1, "name": "Reactive"}]`}
+			client := stubClient(t, transport, RetryPolicy{MaxAttempts: tt.maxAttempts})
+
+			boards, err := client.GetBoards()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if len(transport.calls) != tt.wantCalls {
+				t.Errorf("made %d calls, want %d", len(transport.calls), tt.wantCalls)
+			}
+			if !tt.wantErr && (len(boards) != 1 || boards[0].Name != "Reactive") {
+				t.Errorf("got %+v, want the Reactive board", boards)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	retryAfter := 100 * time.Millisecond
+	transport := &stubTransport{
+		errs: []error{&StatusError{StatusCode: 429, RetryAfter: retryAfter}},
+		body: `[]`,
+	}
+	client := stubClient(t, transport, RetryPolicy{MaxAttempts: 2})
+
+	if _, err := client.GetBoards(); err != nil {
+		t.Fatal(err)
+	}
+	if len(transport.calls) != 2 {
+		t.Fatalf("made %d calls, want 2", len(transport.calls))
+	}
+	if wait := transport.calls[1].Sub(transport.calls[0]); wait < retryAfter {
+		t.Errorf("retried after %s, want at least the Retry-After of %s", wait, retryAfter)
+	}
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	transport := &stubTransport{
+		errs: []error{&StatusError{StatusCode: 429, RetryAfter: time.Minute}},
+		body: `[]`,
+	}
+	client := stubClient(t, transport, RetryPolicy{MaxAttempts: 4, Deadline: time.Second})
+
+	if _, err := client.GetBoards(); err == nil {
+		t.Error("got no error when the Retry-After is past the deadline")
+	}
+	if len(transport.calls) != 1 {
+		t.Errorf("made %d calls, want 1", len(transport.calls))
+	}
+}
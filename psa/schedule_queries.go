@@ -0,0 +1,92 @@
+package psa
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	scheduleEntriesEndpoint string = "/schedule/entries"
+
+	// ticketIDsPerQuery limits the IDs listed in one GET query string
+	ticketIDsPerQuery = 100
+)
+
+// ScheduleEntryFields is for totalling the hours booked
+var ScheduleEntryFields = []string{"id", "objectId", "member", "type", "dateStart", "dateEnd", "hours", "doneFlag"}
+
+func (c *Client) scheduleEntriesCondition(from time.Time, to time.Time) string {
+	return fmt.Sprintf("dateStart >= [%v] AND dateStart < [%v]", timeString(from), timeString(to))
+}
+
+func (c *Client) scheduleEntriesByMemberCondition(memberID int, from time.Time, to time.Time) string {
+	return fmt.Sprintf("member/id = %v AND %v", memberID, c.scheduleEntriesCondition(from, to))
+}
+
+func (c *Client) ticketScheduleEntriesCondition(ticketIDs []int) string {
+	list := make([]string, len(ticketIDs))
+	for i, id := range ticketIDs {
+		list[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("type/identifier = %s AND objectId IN (%s)", quote(ScheduleTypeServiceTicket), strings.Join(list, ","))
+}
+
+// GetScheduleEntries gets the schedule entries of every member starting in
+// a date range, earliest first
+// from: The start of the range
+// to: The end of the range, not included
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetScheduleEntries(from time.Time, to time.Time, fields ...string) ([]ScheduleEntry, error) {
+	return c.GetScheduleEntriesContext(context.Background(), from, to, fields...)
+}
+
+// GetScheduleEntriesContext is GetScheduleEntries with a context
+func (c *Client) GetScheduleEntriesContext(ctx context.Context, from time.Time, to time.Time, fields ...string) ([]ScheduleEntry, error) {
+	return c.getScheduleEntriesCommand(ctx, listQuery(scheduleEntriesEndpoint, c.scheduleEntriesCondition(from, to), "dateStart asc"), fields)
+}
+
+// GetScheduleEntriesByMemberID gets a member's schedule entries starting in
+// a date range, earliest first
+// memberID: The PSA member ID
+// from: The start of the range
+// to: The end of the range, not included
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetScheduleEntriesByMemberID(memberID int, from time.Time, to time.Time, fields ...string) ([]ScheduleEntry, error) {
+	return c.GetScheduleEntriesByMemberIDContext(context.Background(), memberID, from, to, fields...)
+}
+
+// GetScheduleEntriesByMemberIDContext is GetScheduleEntriesByMemberID with a context
+func (c *Client) GetScheduleEntriesByMemberIDContext(ctx context.Context, memberID int, from time.Time, to time.Time, fields ...string) ([]ScheduleEntry, error) {
+	return c.getScheduleEntriesCommand(ctx, listQuery(scheduleEntriesEndpoint, c.scheduleEntriesByMemberCondition(memberID, from, to), "dateStart asc"), fields)
+}
+
+// GetTicketScheduleEntries gets the schedule entries for service tickets,
+// earliest first for each batch of tickets
+// ticketIDs: The PSA ticket IDs
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetTicketScheduleEntries(ticketIDs []int, fields ...string) ([]ScheduleEntry, error) {
+	return c.GetTicketScheduleEntriesContext(context.Background(), ticketIDs, fields...)
+}
+
+// GetTicketScheduleEntriesContext is GetTicketScheduleEntries with a context
+func (c *Client) GetTicketScheduleEntriesContext(ctx context.Context, ticketIDs []int, fields ...string) ([]ScheduleEntry, error) {
+
+	entries := []ScheduleEntry{}
+	for start := 0; start < len(ticketIDs); start += ticketIDsPerQuery {
+		end := start + ticketIDsPerQuery
+		if end > len(ticketIDs) {
+			end = len(ticketIDs)
+		}
+		cmd := listQuery(scheduleEntriesEndpoint, c.ticketScheduleEntriesCondition(ticketIDs[start:end]), "dateStart asc")
+		page, err := c.getScheduleEntriesCommand(ctx, cmd, fields)
+		if err != nil {
+			return []ScheduleEntry{}, err
+		}
+		entries = append(entries, page...)
+	}
+
+	return entries, nil
+}
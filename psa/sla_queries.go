@@ -0,0 +1,28 @@
+package psa
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	slaPrioritiesEndpoint string = "/service/SLAs/%v/priorities"
+)
+
+// GetSLAPriorities gets the response, plan and resolution targets for each
+// priority of an SLA
+// slaID: The PSA SLA ID
+func (c *Client) GetSLAPriorities(slaID int) ([]SLAPriority, error) {
+	return c.GetSLAPrioritiesContext(context.Background(), slaID)
+}
+
+// GetSLAPrioritiesContext is GetSLAPriorities with a context
+func (c *Client) GetSLAPrioritiesContext(ctx context.Context, slaID int) ([]SLAPriority, error) {
+
+	priorities, err := c.getSLAPrioritiesCommand(ctx, fmt.Sprintf(slaPrioritiesEndpoint, slaID))
+	if err != nil {
+		return []SLAPriority{}, err
+	}
+
+	return priorities, nil
+}
@@ -1,19 +1,27 @@
 package psa
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strconv"
 )
 
 const (
-	activeMembersEndpoint string = "/system/members?conditions=disableOnlineFlag=false AND type/ID!=NULL"
-	auditTrailEndpoint    string = "system/audittrail?type=Ticket&id=%v"
+	auditTrailEndpoint string = "system/audittrail?type=Ticket&id=%v"
 )
 
+var activeMembersEndpoint = "/system/members?conditions=" + url.QueryEscape("disableOnlineFlag=false AND type/ID!=NULL")
+
 // GetMembers get active members
 func (c *Client) GetMembers() ([]Member, error) {
+	return c.GetMembersContext(context.Background())
+}
 
-	members, err := c.getMembersCommand(activeMembersEndpoint)
+// GetMembersContext is GetMembers with a context
+func (c *Client) GetMembersContext(ctx context.Context) ([]Member, error) {
+
+	members, err := c.getMembersCommand(ctx, activeMembersEndpoint)
 	if err != nil {
 		return []Member{}, err
 	}
@@ -23,8 +31,13 @@ func (c *Client) GetMembers() ([]Member, error) {
 
 // GetMemberName get the name from a member identifier
 func (c *Client) GetMemberName(identifier string) (string, error) {
+	return c.GetMemberNameContext(context.Background(), identifier)
+}
 
-	members, err := c.getMembersCommand(activeMembersEndpoint)
+// GetMemberNameContext is GetMemberName with a context
+func (c *Client) GetMemberNameContext(ctx context.Context, identifier string) (string, error) {
+
+	members, err := c.getMembersCommand(ctx, activeMembersEndpoint)
 	if err != nil {
 		return "", err
 	}
@@ -40,10 +53,15 @@ func (c *Client) GetMemberName(identifier string) (string, error) {
 
 // GetTicketAuditTrail gets the audit trail entries for a specific ticket
 func (c *Client) GetTicketAuditTrail(ticketID int) ([]Audit, error) {
+	return c.GetTicketAuditTrailContext(context.Background(), ticketID)
+}
+
+// GetTicketAuditTrailContext is GetTicketAuditTrail with a context
+func (c *Client) GetTicketAuditTrailContext(ctx context.Context, ticketID int) ([]Audit, error) {
 
 	cmd := fmt.Sprintf(auditTrailEndpoint, strconv.Itoa(ticketID))
 
-	audit, err := c.getAuditTrailCommand(cmd)
+	audit, err := c.getAuditTrailCommand(ctx, cmd)
 	if err != nil {
 		return []Audit{}, err
 	}
@@ -0,0 +1,126 @@
+package psa
+
+import (
+	"context"
+	"fmt"
+)
+
+// The team queries match the board queries, but select tickets by service
+// team rather than board.
+
+func (c *Client) openTicketsByTeamCondition(teamID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Team/ID = %v", teamID)
+}
+
+func (c *Client) newTicketsByTeamCondition(teamID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("dateEntered >= [%v] AND Team/ID = %v", dateStr, teamID)
+}
+
+func (c *Client) openTicketsByTeamOlderThanCondition(teamID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("ClosedFlag = False AND dateEntered <= [%v] AND Team/ID = %v", dateStr, teamID)
+}
+
+func (c *Client) openNotAssignedTicketsByTeamCondition(teamID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Team/ID = %v AND resources = NULL", teamID)
+}
+
+// GetOpenTicketsByTeamID gets all open tickets for a service team
+// teamID: The PSA team ID
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByTeamID(teamID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByTeamIDContext(context.Background(), teamID, fields...)
+}
+
+// GetOpenTicketsByTeamIDContext is GetOpenTicketsByTeamID with a context
+func (c *Client) GetOpenTicketsByTeamIDContext(ctx context.Context, teamID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsByTeamCondition(teamID), fields...)
+}
+
+// CountOpenTicketsByTeamID counts the open tickets for a service team
+// teamID: The PSA team ID
+func (c *Client) CountOpenTicketsByTeamID(teamID int) (int, error) {
+	return c.CountOpenTicketsByTeamIDContext(context.Background(), teamID)
+}
+
+// CountOpenTicketsByTeamIDContext is CountOpenTicketsByTeamID with a context
+func (c *Client) CountOpenTicketsByTeamIDContext(ctx context.Context, teamID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsByTeamCondition(teamID))
+}
+
+// GetNewTicketsByTeamID gets all new tickets for a service team
+// teamID: The PSA team ID
+// days: New tickets with the last x days
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetNewTicketsByTeamID(teamID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetNewTicketsByTeamIDContext(context.Background(), teamID, days, fields...)
+}
+
+// GetNewTicketsByTeamIDContext is GetNewTicketsByTeamID with a context
+func (c *Client) GetNewTicketsByTeamIDContext(ctx context.Context, teamID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.newTicketsByTeamCondition(teamID, days), fields...)
+}
+
+// CountNewTicketsByTeamID counts the new tickets for a service team
+// teamID: The PSA team ID
+// days: New tickets with the last x days
+func (c *Client) CountNewTicketsByTeamID(teamID int, days int) (int, error) {
+	return c.CountNewTicketsByTeamIDContext(context.Background(), teamID, days)
+}
+
+// CountNewTicketsByTeamIDContext is CountNewTicketsByTeamID with a context
+func (c *Client) CountNewTicketsByTeamIDContext(ctx context.Context, teamID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.newTicketsByTeamCondition(teamID, days))
+}
+
+// GetOpenTicketsByTeamIDOlderThan gets all open tickets for a service team
+// teamID: The PSA team ID
+// days: Tickets entered more than x days ago
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByTeamIDOlderThan(teamID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByTeamIDOlderThanContext(context.Background(), teamID, days, fields...)
+}
+
+// GetOpenTicketsByTeamIDOlderThanContext is GetOpenTicketsByTeamIDOlderThan with a context
+func (c *Client) GetOpenTicketsByTeamIDOlderThanContext(ctx context.Context, teamID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsByTeamOlderThanCondition(teamID, days), fields...)
+}
+
+// CountOpenTicketsByTeamIDOlderThan counts the open tickets for a service
+// team
+// teamID: The PSA team ID
+// days: Tickets entered more than x days ago
+func (c *Client) CountOpenTicketsByTeamIDOlderThan(teamID int, days int) (int, error) {
+	return c.CountOpenTicketsByTeamIDOlderThanContext(context.Background(), teamID, days)
+}
+
+// CountOpenTicketsByTeamIDOlderThanContext is CountOpenTicketsByTeamIDOlderThan with a context
+func (c *Client) CountOpenTicketsByTeamIDOlderThanContext(ctx context.Context, teamID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsByTeamOlderThanCondition(teamID, days))
+}
+
+// GetOpenNotAssignedTicketsByTeamID gets the open tickets for a service team
+// not assigned to anyone
+// teamID: The PSA team ID
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenNotAssignedTicketsByTeamID(teamID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenNotAssignedTicketsByTeamIDContext(context.Background(), teamID, fields...)
+}
+
+// GetOpenNotAssignedTicketsByTeamIDContext is GetOpenNotAssignedTicketsByTeamID with a context
+func (c *Client) GetOpenNotAssignedTicketsByTeamIDContext(ctx context.Context, teamID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openNotAssignedTicketsByTeamCondition(teamID), fields...)
+}
+
+// CountOpenNotAssignedTicketsByTeamID counts the open tickets for a service
+// team not assigned to anyone
+// teamID: The PSA team ID
+func (c *Client) CountOpenNotAssignedTicketsByTeamID(teamID int) (int, error) {
+	return c.CountOpenNotAssignedTicketsByTeamIDContext(context.Background(), teamID)
+}
+
+// CountOpenNotAssignedTicketsByTeamIDContext is CountOpenNotAssignedTicketsByTeamID with a context
+func (c *Client) CountOpenNotAssignedTicketsByTeamIDContext(ctx context.Context, teamID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openNotAssignedTicketsByTeamCondition(teamID))
+}
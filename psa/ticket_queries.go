@@ -1,63 +1,492 @@
 package psa
 
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
 const (
 	ticketsEndpoint      string = "/service/tickets"
 	ticketSearchEndpoint string = "/service/tickets/search"
+	ticketCountEndpoint  string = "/service/tickets/count"
 	ticketSourceEndpoint string = "/service/sources"
+	ticketNotesEndpoint  string = "/service/tickets/%v/notes"
 
 	escalatedText string = "Status has been updated from \"Needs-Info\" to \"Escalated from Helpdesk\"."
 )
 
+// Field lists for the ticket queries. Each query accepts an optional list of
+// fields to return, the full ticket is returned when none are given.
+var (
+	// TicketIDFields is for queries where only the number of tickets matters
+	TicketIDFields = []string{"id"}
+
+	// TicketSummaryFields is for listing tickets
+	TicketSummaryFields = []string{"id", "summary", "company/name", "dateEntered", "resources", "_info/lastUpdated"}
+
+	// TicketSLAFields is for measuring tickets against their SLA
+	TicketSLAFields = []string{"id", "priority", "sla", "dateEntered", "dateResponded", "respondMinutes",
+		"dateResplan", "resPlanMinutes", "dateResolved", "resolveMinutes", "slaStatus", "isInSla"}
+)
+
+// SearchTickets gets all tickets matching the conditions
+// fields: Fields to return, all fields when none are given
+func (c *Client) SearchTickets(conditions string, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(context.Background(), conditions, fields...)
+}
+
+// SearchTicketsContext is SearchTickets with a context
+func (c *Client) SearchTicketsContext(ctx context.Context, conditions string, fields ...string) ([]Ticket, error) {
+	return c.postTicketsCommand(ctx, ticketSearchEndpoint, newCondition("%s", conditions), fields)
+}
+
+// CountTickets gets the number of tickets matching the conditions without
+// returning the tickets themselves
+func (c *Client) CountTickets(conditions string) (int, error) {
+	return c.CountTicketsContext(context.Background(), conditions)
+}
+
+// CountTicketsContext is CountTickets with a context
+func (c *Client) CountTicketsContext(ctx context.Context, conditions string) (int, error) {
+	return c.countCommand(ctx, ticketCountEndpoint, conditions)
+}
+
+// Each board query below has a Get variant returning the tickets and a Count
+// variant returning how many there are, built from the same conditions.
+
+func (c *Client) newTicketsCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("dateEntered >= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) openTicketsCondition(boardID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v", boardID)
+}
+
+func (c *Client) openTicketsOlderThanCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("ClosedFlag = False AND dateEntered <= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) openTicketsNotUpdatedInCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("ClosedFlag = False AND _info/LastUpdated <= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) openAssignedTicketsCondition(boardID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v AND resources LIKE '*'", boardID)
+}
+
+func (c *Client) openNotAssignedTicketsCondition(boardID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v AND resources = NULL", boardID)
+}
+
+func (c *Client) closedTicketsCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("ClosedFlag = True AND closedDate >= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) updatedTicketsCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("_info/LastUpdated >= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) respondedTicketsCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("dateResponded >= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) resolvedTicketsCondition(boardID int, days int) string {
+	dateStr := c.dateStringFromDays(days)
+	return fmt.Sprintf("dateResolved >= [%v] AND Board/ID = %v", dateStr, boardID)
+}
+
+func (c *Client) openTicketsOutOfSLACondition(boardID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v AND isInSla = False", boardID)
+}
+
+func (c *Client) openTicketsInStatusCondition(boardID int, status string) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v AND status/name = %v", boardID, quote(status))
+}
+
+func (c *Client) openCustomerUpdatedTicketsCondition(boardID int) string {
+	return fmt.Sprintf("ClosedFlag = False AND Board/ID = %v AND customerUpdatedFlag = True", boardID)
+}
+
+// GetTicketsByID gets the tickets with the given IDs
+// ids: The PSA ticket IDs
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetTicketsByID(ids []int, fields ...string) ([]Ticket, error) {
+	return c.GetTicketsByIDContext(context.Background(), ids, fields...)
+}
+
+// GetTicketsByIDContext is GetTicketsByID with a context
+func (c *Client) GetTicketsByIDContext(ctx context.Context, ids []int, fields ...string) ([]Ticket, error) {
+	if len(ids) == 0 {
+		return []Ticket{}, nil
+	}
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = strconv.Itoa(id)
+	}
+	return c.SearchTicketsContext(ctx, fmt.Sprintf("id IN (%s)", strings.Join(list, ",")), fields...)
+}
+
+// GetTicketSources gets the ways a ticket can be raised, e.g. email, phone or
+// portal
+func (c *Client) GetTicketSources() ([]TicketSource, error) {
+	return c.GetTicketSourcesContext(context.Background())
+}
+
+// GetTicketSourcesContext is GetTicketSources with a context
+func (c *Client) GetTicketSourcesContext(ctx context.Context) ([]TicketSource, error) {
+
+	sources, err := c.getTicketSourceCommand(ctx, ticketSourceEndpoint)
+	if err != nil {
+		return []TicketSource{}, err
+	}
+
+	return sources, nil
+}
+
+// GetTicketNotes gets the notes on a ticket, oldest first
+// ticketID: The PSA ticket ID
+func (c *Client) GetTicketNotes(ticketID int) ([]TicketNote, error) {
+	return c.GetTicketNotesContext(context.Background(), ticketID)
+}
+
+// GetTicketNotesContext is GetTicketNotes with a context
+func (c *Client) GetTicketNotesContext(ctx context.Context, ticketID int) ([]TicketNote, error) {
+	cmd := fmt.Sprintf(ticketNotesEndpoint, ticketID) + "?orderBy=" + url.QueryEscape("dateCreated asc")
+	return c.getTicketNotesCommand(ctx, cmd)
+}
+
 // GetNewTicketsByBoardID gets all new tickets on a service board
 // boardID: The PSA board ID
 // days: New tickets with the last x days
-func (c *Client) GetNewTicketsByBoardID(boardID int, days int) ([]Ticket, error) {
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetNewTicketsByBoardID(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetNewTicketsByBoardIDContext(context.Background(), boardID, days, fields...)
+}
+
+// GetNewTicketsByBoardIDContext is GetNewTicketsByBoardID with a context
+func (c *Client) GetNewTicketsByBoardIDContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.newTicketsCondition(boardID, days), fields...)
+}
+
+// CountNewTicketsByBoardID counts the new tickets on a service board
+// boardID: The PSA board ID
+// days: New tickets with the last x days
+func (c *Client) CountNewTicketsByBoardID(boardID int, days int) (int, error) {
+	return c.CountNewTicketsByBoardIDContext(context.Background(), boardID, days)
+}
 
-	dateStr := dateStringFromDays(days)
-	conditions := newCondition("dateEntered >= [%v] AND Board/ID = %v", dateStr, boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// CountNewTicketsByBoardIDContext is CountNewTicketsByBoardID with a context
+func (c *Client) CountNewTicketsByBoardIDContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.newTicketsCondition(boardID, days))
 }
 
 // GetOpenTicketsByBoardID gets all open tickets on a service board
 // boardID: The PSA board ID
-func (c *Client) GetOpenTicketsByBoardID(boardID int) ([]Ticket, error) {
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByBoardID(boardID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByBoardIDContext(context.Background(), boardID, fields...)
+}
+
+// GetOpenTicketsByBoardIDContext is GetOpenTicketsByBoardID with a context
+func (c *Client) GetOpenTicketsByBoardIDContext(ctx context.Context, boardID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsCondition(boardID), fields...)
+}
+
+// CountOpenTicketsByBoardID counts the open tickets on a service board
+// boardID: The PSA board ID
+func (c *Client) CountOpenTicketsByBoardID(boardID int) (int, error) {
+	return c.CountOpenTicketsByBoardIDContext(context.Background(), boardID)
+}
 
-	conditions := newCondition("ClosedFlag = False AND Board/ID = %v", boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// CountOpenTicketsByBoardIDContext is CountOpenTicketsByBoardID with a context
+func (c *Client) CountOpenTicketsByBoardIDContext(ctx context.Context, boardID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsCondition(boardID))
 }
 
 // GetOpenTicketsByBoardIDOlderThan gets all open tickets on a service board
 // boardID: The PSA board ID
-// days:
-func (c *Client) GetOpenTicketsByBoardIDOlderThan(boardID int, days int) ([]Ticket, error) {
+// days: Tickets entered more than x days ago
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByBoardIDOlderThan(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByBoardIDOlderThanContext(context.Background(), boardID, days, fields...)
+}
+
+// GetOpenTicketsByBoardIDOlderThanContext is GetOpenTicketsByBoardIDOlderThan with a context
+func (c *Client) GetOpenTicketsByBoardIDOlderThanContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsOlderThanCondition(boardID, days), fields...)
+}
+
+// CountOpenTicketsByBoardIDOlderThan counts the open tickets on a service board
+// boardID: The PSA board ID
+// days: Tickets entered more than x days ago
+func (c *Client) CountOpenTicketsByBoardIDOlderThan(boardID int, days int) (int, error) {
+	return c.CountOpenTicketsByBoardIDOlderThanContext(context.Background(), boardID, days)
+}
 
-	dateStr := dateStringFromDays(days)
-	conditions := newCondition("ClosedFlag = False AND dateEntered <= [%v] AND Board/ID = %v", dateStr, boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// CountOpenTicketsByBoardIDOlderThanContext is CountOpenTicketsByBoardIDOlderThan with a context
+func (c *Client) CountOpenTicketsByBoardIDOlderThanContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsOlderThanCondition(boardID, days))
 }
 
 // GetOpenTicketsByBoardIDNotUpdatedIn gets all open tickets on a service board
 // boardID: The PSA board ID
 // days: Tickets that have not been upated in x days
-func (c *Client) GetOpenTicketsByBoardIDNotUpdatedIn(boardID int, days int) ([]Ticket, error) {
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByBoardIDNotUpdatedIn(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByBoardIDNotUpdatedInContext(context.Background(), boardID, days, fields...)
+}
+
+// GetOpenTicketsByBoardIDNotUpdatedInContext is GetOpenTicketsByBoardIDNotUpdatedIn with a context
+func (c *Client) GetOpenTicketsByBoardIDNotUpdatedInContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsNotUpdatedInCondition(boardID, days), fields...)
+}
+
+// CountOpenTicketsByBoardIDNotUpdatedIn counts the open tickets on a service board
+// boardID: The PSA board ID
+// days: Tickets that have not been upated in x days
+func (c *Client) CountOpenTicketsByBoardIDNotUpdatedIn(boardID int, days int) (int, error) {
+	return c.CountOpenTicketsByBoardIDNotUpdatedInContext(context.Background(), boardID, days)
+}
 
-	dateStr := dateStringFromDays(days)
-	conditions := newCondition("ClosedFlag = False AND _info/LastUpdated <= [%v] AND Board/ID = %v", dateStr, boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// CountOpenTicketsByBoardIDNotUpdatedInContext is CountOpenTicketsByBoardIDNotUpdatedIn with a context
+func (c *Client) CountOpenTicketsByBoardIDNotUpdatedInContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsNotUpdatedInCondition(boardID, days))
 }
 
 // GetOpenAssignedTicketsByBoardID gets all open tickets on a service board
 // boardID: The PSA board ID
-func (c *Client) GetOpenAssignedTicketsByBoardID(boardID int) ([]Ticket, error) {
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenAssignedTicketsByBoardID(boardID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenAssignedTicketsByBoardIDContext(context.Background(), boardID, fields...)
+}
 
-	conditions := newCondition("ClosedFlag = False AND Board/ID = %v AND resources LIKE '*'", boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// GetOpenAssignedTicketsByBoardIDContext is GetOpenAssignedTicketsByBoardID with a context
+func (c *Client) GetOpenAssignedTicketsByBoardIDContext(ctx context.Context, boardID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openAssignedTicketsCondition(boardID), fields...)
+}
+
+// CountOpenAssignedTicketsByBoardID counts the open tickets on a service board
+// boardID: The PSA board ID
+func (c *Client) CountOpenAssignedTicketsByBoardID(boardID int) (int, error) {
+	return c.CountOpenAssignedTicketsByBoardIDContext(context.Background(), boardID)
+}
+
+// CountOpenAssignedTicketsByBoardIDContext is CountOpenAssignedTicketsByBoardID with a context
+func (c *Client) CountOpenAssignedTicketsByBoardIDContext(ctx context.Context, boardID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openAssignedTicketsCondition(boardID))
 }
 
 // GetOpenNotAssignedTicketsByBoardID gets all open tickets on a service board
 // boardID: The PSA board ID
-func (c *Client) GetOpenNotAssignedTicketsByBoardID(boardID int) ([]Ticket, error) {
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenNotAssignedTicketsByBoardID(boardID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenNotAssignedTicketsByBoardIDContext(context.Background(), boardID, fields...)
+}
+
+// GetOpenNotAssignedTicketsByBoardIDContext is GetOpenNotAssignedTicketsByBoardID with a context
+func (c *Client) GetOpenNotAssignedTicketsByBoardIDContext(ctx context.Context, boardID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openNotAssignedTicketsCondition(boardID), fields...)
+}
+
+// CountOpenNotAssignedTicketsByBoardID counts the open tickets on a service board
+// boardID: The PSA board ID
+func (c *Client) CountOpenNotAssignedTicketsByBoardID(boardID int) (int, error) {
+	return c.CountOpenNotAssignedTicketsByBoardIDContext(context.Background(), boardID)
+}
+
+// CountOpenNotAssignedTicketsByBoardIDContext is CountOpenNotAssignedTicketsByBoardID with a context
+func (c *Client) CountOpenNotAssignedTicketsByBoardIDContext(ctx context.Context, boardID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openNotAssignedTicketsCondition(boardID))
+}
+
+// GetClosedTicketsByBoardID gets the tickets closed on a service board
+// boardID: The PSA board ID
+// days: Tickets closed within the last x days
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetClosedTicketsByBoardID(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetClosedTicketsByBoardIDContext(context.Background(), boardID, days, fields...)
+}
+
+// GetClosedTicketsByBoardIDContext is GetClosedTicketsByBoardID with a context
+func (c *Client) GetClosedTicketsByBoardIDContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.closedTicketsCondition(boardID, days), fields...)
+}
+
+// CountClosedTicketsByBoardID counts the tickets closed on a service board
+// boardID: The PSA board ID
+// days: Tickets closed within the last x days
+func (c *Client) CountClosedTicketsByBoardID(boardID int, days int) (int, error) {
+	return c.CountClosedTicketsByBoardIDContext(context.Background(), boardID, days)
+}
+
+// CountClosedTicketsByBoardIDContext is CountClosedTicketsByBoardID with a context
+func (c *Client) CountClosedTicketsByBoardIDContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.closedTicketsCondition(boardID, days))
+}
+
+// GetOpenCustomerUpdatedTicketsByBoardID gets the open tickets on a service
+// board the customer has updated since a member last replied
+// boardID: The PSA board ID
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenCustomerUpdatedTicketsByBoardID(boardID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenCustomerUpdatedTicketsByBoardIDContext(context.Background(), boardID, fields...)
+}
+
+// GetOpenCustomerUpdatedTicketsByBoardIDContext is GetOpenCustomerUpdatedTicketsByBoardID with a context
+func (c *Client) GetOpenCustomerUpdatedTicketsByBoardIDContext(ctx context.Context, boardID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openCustomerUpdatedTicketsCondition(boardID), fields...)
+}
+
+// CountOpenCustomerUpdatedTicketsByBoardID counts the open tickets on a
+// service board the customer has updated since a member last replied
+// boardID: The PSA board ID
+func (c *Client) CountOpenCustomerUpdatedTicketsByBoardID(boardID int) (int, error) {
+	return c.CountOpenCustomerUpdatedTicketsByBoardIDContext(context.Background(), boardID)
+}
+
+// CountOpenCustomerUpdatedTicketsByBoardIDContext is CountOpenCustomerUpdatedTicketsByBoardID with a context
+func (c *Client) CountOpenCustomerUpdatedTicketsByBoardIDContext(ctx context.Context, boardID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openCustomerUpdatedTicketsCondition(boardID))
+}
+
+// GetTicketsByBoardIDUpdatedIn gets the open and closed tickets on a service
+// board that have been updated recently
+// boardID: The PSA board ID
+// days: Tickets updated within the last x days
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetTicketsByBoardIDUpdatedIn(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetTicketsByBoardIDUpdatedInContext(context.Background(), boardID, days, fields...)
+}
+
+// GetTicketsByBoardIDUpdatedInContext is GetTicketsByBoardIDUpdatedIn with a context
+func (c *Client) GetTicketsByBoardIDUpdatedInContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.updatedTicketsCondition(boardID, days), fields...)
+}
+
+// CountTicketsByBoardIDUpdatedIn counts the open and closed tickets on a
+// service board that have been updated recently
+// boardID: The PSA board ID
+// days: Tickets updated within the last x days
+func (c *Client) CountTicketsByBoardIDUpdatedIn(boardID int, days int) (int, error) {
+	return c.CountTicketsByBoardIDUpdatedInContext(context.Background(), boardID, days)
+}
+
+// CountTicketsByBoardIDUpdatedInContext is CountTicketsByBoardIDUpdatedIn with a context
+func (c *Client) CountTicketsByBoardIDUpdatedInContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.updatedTicketsCondition(boardID, days))
+}
+
+// GetRespondedTicketsByBoardID gets the tickets on a service board first
+// responded to recently
+// boardID: The PSA board ID
+// days: Tickets responded to within the last x days
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetRespondedTicketsByBoardID(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetRespondedTicketsByBoardIDContext(context.Background(), boardID, days, fields...)
+}
+
+// GetRespondedTicketsByBoardIDContext is GetRespondedTicketsByBoardID with a context
+func (c *Client) GetRespondedTicketsByBoardIDContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.respondedTicketsCondition(boardID, days), fields...)
+}
+
+// CountRespondedTicketsByBoardID counts the tickets on a service board first
+// responded to recently
+// boardID: The PSA board ID
+// days: Tickets responded to within the last x days
+func (c *Client) CountRespondedTicketsByBoardID(boardID int, days int) (int, error) {
+	return c.CountRespondedTicketsByBoardIDContext(context.Background(), boardID, days)
+}
+
+// CountRespondedTicketsByBoardIDContext is CountRespondedTicketsByBoardID with a context
+func (c *Client) CountRespondedTicketsByBoardIDContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.respondedTicketsCondition(boardID, days))
+}
+
+// GetResolvedTicketsByBoardID gets the tickets on a service board resolved
+// recently
+// boardID: The PSA board ID
+// days: Tickets resolved within the last x days
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetResolvedTicketsByBoardID(boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.GetResolvedTicketsByBoardIDContext(context.Background(), boardID, days, fields...)
+}
+
+// GetResolvedTicketsByBoardIDContext is GetResolvedTicketsByBoardID with a context
+func (c *Client) GetResolvedTicketsByBoardIDContext(ctx context.Context, boardID int, days int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.resolvedTicketsCondition(boardID, days), fields...)
+}
+
+// CountResolvedTicketsByBoardID counts the tickets on a service board
+// resolved recently
+// boardID: The PSA board ID
+// days: Tickets resolved within the last x days
+func (c *Client) CountResolvedTicketsByBoardID(boardID int, days int) (int, error) {
+	return c.CountResolvedTicketsByBoardIDContext(context.Background(), boardID, days)
+}
+
+// CountResolvedTicketsByBoardIDContext is CountResolvedTicketsByBoardID with a context
+func (c *Client) CountResolvedTicketsByBoardIDContext(ctx context.Context, boardID int, days int) (int, error) {
+	return c.CountTicketsContext(ctx, c.resolvedTicketsCondition(boardID, days))
+}
+
+// GetOpenTicketsByBoardIDOutOfSLA gets the open tickets on a service board
+// that have breached their SLA
+// boardID: The PSA board ID
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByBoardIDOutOfSLA(boardID int, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByBoardIDOutOfSLAContext(context.Background(), boardID, fields...)
+}
+
+// GetOpenTicketsByBoardIDOutOfSLAContext is GetOpenTicketsByBoardIDOutOfSLA with a context
+func (c *Client) GetOpenTicketsByBoardIDOutOfSLAContext(ctx context.Context, boardID int, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsOutOfSLACondition(boardID), fields...)
+}
+
+// CountOpenTicketsByBoardIDOutOfSLA counts the open tickets on a service
+// board that have breached their SLA
+// boardID: The PSA board ID
+func (c *Client) CountOpenTicketsByBoardIDOutOfSLA(boardID int) (int, error) {
+	return c.CountOpenTicketsByBoardIDOutOfSLAContext(context.Background(), boardID)
+}
+
+// CountOpenTicketsByBoardIDOutOfSLAContext is CountOpenTicketsByBoardIDOutOfSLA with a context
+func (c *Client) CountOpenTicketsByBoardIDOutOfSLAContext(ctx context.Context, boardID int) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsOutOfSLACondition(boardID))
+}
+
+// GetOpenTicketsByBoardIDInStatus gets the open tickets on a service board
+// with the given status
+// boardID: The PSA board ID
+// status: The status name
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetOpenTicketsByBoardIDInStatus(boardID int, status string, fields ...string) ([]Ticket, error) {
+	return c.GetOpenTicketsByBoardIDInStatusContext(context.Background(), boardID, status, fields...)
+}
+
+// GetOpenTicketsByBoardIDInStatusContext is GetOpenTicketsByBoardIDInStatus with a context
+func (c *Client) GetOpenTicketsByBoardIDInStatusContext(ctx context.Context, boardID int, status string, fields ...string) ([]Ticket, error) {
+	return c.SearchTicketsContext(ctx, c.openTicketsInStatusCondition(boardID, status), fields...)
+}
+
+// CountOpenTicketsByBoardIDInStatus counts the open tickets on a service
+// board with the given status
+// boardID: The PSA board ID
+// status: The status name
+func (c *Client) CountOpenTicketsByBoardIDInStatus(boardID int, status string) (int, error) {
+	return c.CountOpenTicketsByBoardIDInStatusContext(context.Background(), boardID, status)
+}
 
-	conditions := newCondition("ClosedFlag = False AND Board/ID = %v AND resources = NULL", boardID)
-	return c.postTicketsCommand(ticketSearchEndpoint, conditions)
+// CountOpenTicketsByBoardIDInStatusContext is CountOpenTicketsByBoardIDInStatus with a context
+func (c *Client) CountOpenTicketsByBoardIDInStatusContext(ctx context.Context, boardID int, status string) (int, error) {
+	return c.CountTicketsContext(ctx, c.openTicketsInStatusCondition(boardID, status))
 }
@@ -0,0 +1,325 @@
+package psa_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/simononebyte/scorecard/psa/psatest"
+)
+
+var now = time.Date(2020, 3, 9, 12, 0, 0, 0, time.UTC)
+
+// pagingTickets is enough tickets on the first board for its open tickets
+// to span more than one page of 1000
+const pagingTickets = 1560
+
+// newTestServer starts a psatest.Server holding n tickets on one board and
+// a few on another, spread across every condition the queries use
+func newTestServer(t *testing.T, n int) (*psatest.Server, *psa.Client) {
+	t.Helper()
+	s := psatest.NewServer()
+
+	s.Boards = []psa.Board{{ID: 1, Name: "Reactive"}, {ID: 2, Name: "Projects"}}
+	statuses := []psa.Status{{ID: 1, Name: "New"}, {ID: 2, Name: "Waiting on Customer"}, {ID: 3, Name: "In Progress"}}
+	for i := 0; i < n+30; i++ {
+		board := s.Boards[0]
+		if i >= n {
+			board = s.Boards[1]
+		}
+		entered := now.AddDate(0, 0, -(i % 40)).Add(-time.Hour)
+		ticket := psa.Ticket{
+			ID:          1000 + i,
+			Summary:     fmt.Sprintf("Ticket %d", i),
+			Board:       board,
+			Status:      statuses[i%len(statuses)],
+			Team:        psa.Reference{ID: i/2%2 + 1},
+			DateEntered: entered,
+			ClosedFlag:  i%3 == 0,
+			CustUpdated: i%5 == 0,
+			IsInSLA:     i%4 != 0,
+			Info:        psa.Info{LastUpdated: now.AddDate(0, 0, -(i % 20)).Add(-time.Hour)},
+		}
+		if i%2 == 0 {
+			ticket.Resources = "jbloggs"
+		}
+		if ticket.ClosedFlag {
+			ticket.ClosedDate = entered.AddDate(0, 0, i%5)
+		}
+		if i%3 != 1 {
+			ticket.DateResponded = entered.Add(time.Hour)
+			ticket.DateResolved = entered.AddDate(0, 0, i%6)
+		}
+		s.Tickets = append(s.Tickets, ticket)
+	}
+
+	c := s.Config()
+	c.Clock = func() time.Time { return now }
+	client, err := psa.NewClient(c, nil)
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+// queryPair is a Get query and the Count query built from the same
+// conditions
+type queryPair struct {
+	name  string
+	get   func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error)
+	count func(ctx context.Context, c *psa.Client) (int, error)
+}
+
+func boardQueryPairs(boardID int) []queryPair {
+	return []queryPair{
+		{"New",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetNewTicketsByBoardIDContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountNewTicketsByBoardIDContext(ctx, boardID, 7)
+			}},
+		{"Open",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByBoardIDContext(ctx, boardID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByBoardIDContext(ctx, boardID)
+			}},
+		{"OlderThan",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 7)
+			}},
+		{"NotUpdatedIn",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByBoardIDNotUpdatedInContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByBoardIDNotUpdatedInContext(ctx, boardID, 7)
+			}},
+		{"Assigned",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenAssignedTicketsByBoardIDContext(ctx, boardID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenAssignedTicketsByBoardIDContext(ctx, boardID)
+			}},
+		{"NotAssigned",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenNotAssignedTicketsByBoardIDContext(ctx, boardID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenNotAssignedTicketsByBoardIDContext(ctx, boardID)
+			}},
+		{"Closed",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetClosedTicketsByBoardIDContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountClosedTicketsByBoardIDContext(ctx, boardID, 7)
+			}},
+		{"CustomerUpdated",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenCustomerUpdatedTicketsByBoardIDContext(ctx, boardID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenCustomerUpdatedTicketsByBoardIDContext(ctx, boardID)
+			}},
+		{"UpdatedIn",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetTicketsByBoardIDUpdatedInContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountTicketsByBoardIDUpdatedInContext(ctx, boardID, 7)
+			}},
+		{"Responded",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetRespondedTicketsByBoardIDContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountRespondedTicketsByBoardIDContext(ctx, boardID, 7)
+			}},
+		{"Resolved",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetResolvedTicketsByBoardIDContext(ctx, boardID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountResolvedTicketsByBoardIDContext(ctx, boardID, 7)
+			}},
+		{"OutOfSLA",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByBoardIDOutOfSLAContext(ctx, boardID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByBoardIDOutOfSLAContext(ctx, boardID)
+			}},
+		{"InStatus",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByBoardIDInStatusContext(ctx, boardID, "Waiting on Customer", psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByBoardIDInStatusContext(ctx, boardID, "Waiting on Customer")
+			}},
+	}
+}
+
+func teamQueryPairs(teamID int) []queryPair {
+	return []queryPair{
+		{"TeamOpen",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByTeamIDContext(ctx, teamID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByTeamIDContext(ctx, teamID)
+			}},
+		{"TeamNew",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetNewTicketsByTeamIDContext(ctx, teamID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountNewTicketsByTeamIDContext(ctx, teamID, 7)
+			}},
+		{"TeamOlderThan",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenTicketsByTeamIDOlderThanContext(ctx, teamID, 7, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenTicketsByTeamIDOlderThanContext(ctx, teamID, 7)
+			}},
+		{"TeamNotAssigned",
+			func(ctx context.Context, c *psa.Client) ([]psa.Ticket, error) {
+				return c.GetOpenNotAssignedTicketsByTeamIDContext(ctx, teamID, psa.TicketIDFields...)
+			},
+			func(ctx context.Context, c *psa.Client) (int, error) {
+				return c.CountOpenNotAssignedTicketsByTeamIDContext(ctx, teamID)
+			}},
+	}
+}
+
+func TestCountsMatchLists(t *testing.T) {
+	s, client := newTestServer(t, 120)
+	defer s.Close()
+	ctx := context.Background()
+
+	pairs := []queryPair{}
+	for _, boardID := range []int{1, 2} {
+		for _, p := range boardQueryPairs(boardID) {
+			p.name = fmt.Sprintf("Board%d/%s", boardID, p.name)
+			pairs = append(pairs, p)
+		}
+	}
+	pairs = append(pairs, teamQueryPairs(1)...)
+
+	for _, p := range pairs {
+		t.Run(p.name, func(t *testing.T) {
+			tickets, err := p.get(ctx, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := p.count(ctx, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tickets) != n {
+				t.Errorf("Get returned %d tickets, Count %d", len(tickets), n)
+			}
+			if n == 0 {
+				t.Errorf("no tickets match, the test data should cover every query")
+			}
+		})
+	}
+}
+
+func TestGetPagesPastOneThousand(t *testing.T) {
+	s, client := newTestServer(t, pagingTickets)
+	defer s.Close()
+	ctx := context.Background()
+
+	tickets, err := client.GetOpenTicketsByBoardIDContext(ctx, 1, psa.TicketIDFields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := client.CountOpenTicketsByBoardIDContext(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n <= 1000 {
+		t.Fatalf("Count = %d, want more than one page of 1000", n)
+	}
+	if len(tickets) != n {
+		t.Errorf("Get returned %d tickets, Count %d", len(tickets), n)
+	}
+
+	seen := map[int]bool{}
+	for _, ticket := range tickets {
+		if seen[ticket.ID] {
+			t.Fatalf("ticket %d returned twice", ticket.ID)
+		}
+		seen[ticket.ID] = true
+	}
+
+	pages := 0
+	for _, r := range s.Requests {
+		if strings.HasPrefix(r, "POST ") {
+			pages++
+		}
+	}
+	if pages != 2 {
+		t.Errorf("made %d search requests, want 2 pages", pages)
+	}
+}
+
+func TestGetTicketsByID(t *testing.T) {
+	s, client := newTestServer(t, 10)
+	defer s.Close()
+
+	tickets, err := client.GetTicketsByID([]int{1000, 1005, 99999}, "id", "board/id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 2 || tickets[0].ID != 1000 || tickets[1].ID != 1005 {
+		t.Errorf("got %+v, want tickets 1000 and 1005", tickets)
+	}
+
+	requests := len(s.Requests)
+	tickets, err = client.GetTicketsByID(nil)
+	if err != nil || len(tickets) != 0 {
+		t.Errorf("got %v %v, want no tickets", tickets, err)
+	}
+	if len(s.Requests) != requests {
+		t.Errorf("made a request for no IDs")
+	}
+}
+
+func TestInStatusQuotesStatusName(t *testing.T) {
+	s, client := newTestServer(t, 0)
+	defer s.Close()
+
+	statuses := []string{`Waiting on "Vendor"`, "Customer's Reply", `Escalated \ 2nd Line`, `Waiting on "Vendor" \`}
+	for i, name := range statuses {
+		s.Tickets = append(s.Tickets, psa.Ticket{
+			ID: 1 + i, Board: s.Boards[0], Status: psa.Status{ID: 10 + i, Name: name},
+		})
+	}
+
+	for _, name := range statuses {
+		tickets, err := client.GetOpenTicketsByBoardIDInStatus(1, name)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if len(tickets) != 1 || tickets[0].Status.Name != name {
+			t.Errorf("%s: got %+v, want just the ticket in that status", name, tickets)
+		}
+		if n, err := client.CountOpenTicketsByBoardIDInStatus(1, name); err != nil || n != 1 {
+			t.Errorf("%s: counted %d %v, want 1", name, n, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package psa
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	timeEntriesEndpoint string = "/time/entries"
+)
+
+// TimeEntryFields is for totalling the hours logged
+var TimeEntryFields = []string{"id", "chargeToId", "chargeToType", "member", "timeStart",
+	"actualHours", "billableOption"}
+
+func timeString(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func (c *Client) timeEntriesCondition(from time.Time, to time.Time) string {
+	return fmt.Sprintf("timeStart >= [%v] AND timeStart < [%v]", timeString(from), timeString(to))
+}
+
+func (c *Client) timeEntriesByMemberCondition(memberID int, from time.Time, to time.Time) string {
+	return fmt.Sprintf("member/id = %v AND %v", memberID, c.timeEntriesCondition(from, to))
+}
+
+// GetTimeEntries gets the time entries of every member started in a date
+// range, oldest first
+// from: The start of the range
+// to: The end of the range, not included
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetTimeEntries(from time.Time, to time.Time, fields ...string) ([]TimeEntry, error) {
+	return c.GetTimeEntriesContext(context.Background(), from, to, fields...)
+}
+
+// GetTimeEntriesContext is GetTimeEntries with a context
+func (c *Client) GetTimeEntriesContext(ctx context.Context, from time.Time, to time.Time, fields ...string) ([]TimeEntry, error) {
+	return c.getTimeEntriesCommand(ctx, listQuery(timeEntriesEndpoint, c.timeEntriesCondition(from, to), "timeStart asc"), fields)
+}
+
+// GetTimeEntriesByMemberID gets a member's time entries started in a date
+// range, oldest first
+// memberID: The PSA member ID
+// from: The start of the range
+// to: The end of the range, not included
+// fields: Fields to return, all fields when none are given
+func (c *Client) GetTimeEntriesByMemberID(memberID int, from time.Time, to time.Time, fields ...string) ([]TimeEntry, error) {
+	return c.GetTimeEntriesByMemberIDContext(context.Background(), memberID, from, to, fields...)
+}
+
+// GetTimeEntriesByMemberIDContext is GetTimeEntriesByMemberID with a context
+func (c *Client) GetTimeEntriesByMemberIDContext(ctx context.Context, memberID int, from time.Time, to time.Time, fields ...string) ([]TimeEntry, error) {
+	return c.getTimeEntriesCommand(ctx, listQuery(timeEntriesEndpoint, c.timeEntriesByMemberCondition(memberID, from, to), "timeStart asc"), fields)
+}
+
+// listQuery adds the conditions and sort order to a GET list endpoint
+func listQuery(endpoint string, conditions string, orderBy string) string {
+	return endpoint + "?conditions=" + url.QueryEscape(conditions) + "&orderBy=" + url.QueryEscape(orderBy)
+}
@@ -0,0 +1,153 @@
+package psa
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport sends a request to the API and decodes the JSON response into v.
+// cmd is the path and query relative to the API base. body is nil for GET.
+type Transport interface {
+	Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) error
+}
+
+// HTTPTransport is the default Transport, sending requests with net/http
+// using basic authentication
+type HTTPTransport struct {
+	Base    string
+	Headers map[string]string
+	Client  *http.Client
+
+	// OnRequest is called after each request, e.g. for debug logging
+	OnRequest func(RequestLog)
+}
+
+// RequestLog describes a completed request. It holds no headers, so no
+// credentials.
+type RequestLog struct {
+	Method     string
+	Endpoint   string
+	Conditions string
+	Page       int
+	Status     int
+	Duration   time.Duration
+	Err        error
+}
+
+// NewHTTPTransport creates an HTTPTransport authenticating with token, which
+// is the user:password pair for basic authentication
+func NewHTTPTransport(base string, token string) *HTTPTransport {
+	return &HTTPTransport{
+		Base: base,
+		Headers: map[string]string{
+			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(token)),
+			"Accept":        "application/json",
+		},
+		Client: http.DefaultClient,
+	}
+}
+
+// Do implements Transport. Responses other than 2xx are returned as a
+// *StatusError.
+func (t *HTTPTransport) Do(ctx context.Context, method string, cmd string, body interface{}, v interface{}) (err error) {
+
+	if t.OnRequest != nil {
+		entry := newRequestLog(method, cmd, body)
+		start := time.Now()
+		defer func() {
+			entry.Duration = time.Since(start)
+			entry.Err = err
+			if se, ok := err.(*StatusError); ok {
+				entry.Status = se.StatusCode
+			} else if err == nil {
+				entry.Status = http.StatusOK
+			}
+			t.OnRequest(entry)
+		}()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, joinURL(t.Base, cmd), reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Message:    strings.TrimSpace(string(msg)),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return &decodeError{method, cmd, err}
+	}
+	return nil
+}
+
+func newRequestLog(method string, cmd string, body interface{}) RequestLog {
+	entry := RequestLog{Method: method, Endpoint: cmd}
+	if u, err := url.Parse(cmd); err == nil {
+		entry.Endpoint = u.Path
+		entry.Conditions = u.Query().Get("conditions")
+		entry.Page, _ = strconv.Atoi(u.Query().Get("page"))
+	}
+	if q, ok := body.(map[string]string); ok && q["conditions"] != "" {
+		entry.Conditions = q["conditions"]
+	}
+	return entry
+}
+
+func joinURL(base string, cmd string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(cmd, "/")
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as a date
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// setupRecording points the PSA and RMM clients at a recorder or a replay of
+// an earlier recording. When replaying, the clock is pinned to the time of
+// the recording so date based queries match what was recorded.
+//
+// Recordings are written as each request completes, so nothing is lost if
+// the process exits without closing the recorder.
+func setupRecording(c *config, recordDir string, replayDir string) error {
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("-record and -replay cannot be used together")
+
+	case recordDir != "":
+		recorder, err := psa.NewRecorder(recordDir, clock())
+		if err != nil {
+			return fmt.Errorf("error creating recording: %s", err)
+		}
+		c.ConnectWise.Transport = recorder.Wrap("psa", psa.NewDefaultTransport(c.ConnectWise))
+		c.rmmTransport = recorder.Wrap("rmm", newRMMTransport(*c))
+
+	case replayDir != "":
+		replayer, err := psa.NewReplayer(replayDir)
+		if err != nil {
+			return err
+		}
+		recordedAt := replayer.RecordedAt()
+		clock = func() time.Time { return recordedAt }
+		c.ConnectWise.Transport = replayer.Transport("psa")
+		c.ConnectWise.Clock = clock
+		c.rmmTransport = replayer.Transport("rmm")
+		logger.Info("replaying API traffic", "dir", replayDir, "recorded_at", recordedAt)
+	}
+	return nil
+}
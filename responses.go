@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// responseFields are the ticket fields the response stats need
+var responseFields = []string{"id", "dateEntered", "closedFlag", "closedDate"}
+
+// collectResponseStats works out, from the ticket notes:
+//
+// firstResponseHours, the average time from a ticket being entered in the
+// last week to the first note by a member. A ticket with no response yet
+// counts at its age so far, or until it was closed, so the tickets waiting
+// longest are not left out.
+//
+// touchesPerClosed, the average number of member notes on tickets closed in
+// the last week
+func collectResponseStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	stats := extraStats{}
+	notes := ticketNotes{client: client, notes: map[int][]psa.TicketNote{}}
+
+	if board.hasExtra("firstResponseHours") {
+		tickets, err := client.GetNewTicketsByBoardIDContext(ctx, board.ID, 7, responseFields...)
+		if err != nil {
+			return nil, err
+		}
+		hours := []float64{}
+		for _, t := range tickets {
+			ticketNotes, err := notes.get(ctx, t.ID)
+			if err != nil {
+				return nil, err
+			}
+			hours = append(hours, firstResponse(t, ticketNotes).Sub(t.DateEntered).Hours())
+		}
+		if avg, ok := average(hours); ok {
+			stats["firstResponseHours"] = avg
+		}
+	}
+
+	if board.hasExtra("touchesPerClosed") {
+		tickets, err := client.GetClosedTicketsByBoardIDContext(ctx, board.ID, 7, responseFields...)
+		if err != nil {
+			return nil, err
+		}
+		touches := []float64{}
+		for _, t := range tickets {
+			ticketNotes, err := notes.get(ctx, t.ID)
+			if err != nil {
+				return nil, err
+			}
+			n := 0
+			for _, note := range ticketNotes {
+				if note.IsMemberResponse() {
+					n++
+				}
+			}
+			touches = append(touches, float64(n))
+		}
+		if avg, ok := average(touches); ok {
+			stats["touchesPerClosed"] = avg
+		}
+	}
+
+	return stats, nil
+}
+
+// firstResponse is when a member first added a note to the ticket. For a
+// ticket with no response yet it is the time it was closed, or now if it is
+// still open.
+func firstResponse(t psa.Ticket, notes []psa.TicketNote) time.Time {
+	for _, n := range notes {
+		if n.IsMemberResponse() {
+			return n.DateCreated
+		}
+	}
+	if t.ClosedFlag && !t.ClosedDate.IsZero() {
+		return t.ClosedDate
+	}
+	return clock()
+}
+
+// collectCustomerUpdatedStats counts the open tickets the customer has
+// updated that are awaiting a reply
+func collectCustomerUpdatedStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	n, err := client.CountOpenCustomerUpdatedTicketsByBoardIDContext(ctx, board.ID)
+	if err != nil {
+		return nil, err
+	}
+	return extraStats{"customerUpdated": float64(n)}, nil
+}
+
+// ticketNotes fetches the notes for each ticket once, as a ticket entered
+// and closed in the same week is needed for both stats
+type ticketNotes struct {
+	client *psa.Client
+	notes  map[int][]psa.TicketNote
+}
+
+func (t ticketNotes) get(ctx context.Context, ticketID int) ([]psa.TicketNote, error) {
+	if notes, ok := t.notes[ticketID]; ok {
+		return notes, nil
+	}
+	notes, err := t.client.GetTicketNotesContext(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	t.notes[ticketID] = notes
+	return notes, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/simononebyte/scorecard/psa/psatest"
+	"github.com/tealeg/xlsx"
+)
+
+func TestFirstResponseHours(t *testing.T) {
+	defer func(c func() time.Time) { clock = c }(clock)
+	clock = func() time.Time { return testNow }
+
+	s := psatest.NewServer()
+	defer s.Close()
+	board := psa.Board{ID: 1, Name: "Reactive"}
+	s.Boards = []psa.Board{board}
+
+	ago := func(hours int) time.Time { return testNow.Add(-time.Duration(hours) * time.Hour) }
+	tech := psa.Member{ID: 10, Identifier: "jbloggs"}
+	s.Tickets = []psa.Ticket{
+		{ID: 1, Board: board, DateEntered: ago(48)},
+		{ID: 2, Board: board, DateEntered: ago(10)},
+		{ID: 3, Board: board, DateEntered: ago(30), ClosedFlag: true, ClosedDate: ago(24)},
+		{ID: 4, Board: board, DateEntered: ago(20)},
+		{ID: 5, Board: board, DateEntered: ago(24 * 10)},
+	}
+	s.Notes[1] = []psa.TicketNote{
+		{ID: 1, DetailDescriptionFlag: true, Member: tech, DateCreated: ago(48)},
+		{ID: 2, Member: tech, DateCreated: ago(46)},
+		{ID: 3, Member: tech, DateCreated: ago(40)},
+	}
+	s.Notes[4] = []psa.TicketNote{
+		{ID: 4, DetailDescriptionFlag: true, Member: tech, DateCreated: ago(20)},
+		{ID: 5, Contact: psa.Contact{ID: 7}, DateCreated: ago(19)},
+	}
+
+	c := s.Config()
+	c.Clock = clock
+	client, err := psa.NewClient(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Answered after 2 hours, waiting 10 hours so far, closed unanswered
+	// after 6 hours and waiting 20 hours with only the customer's notes
+	b := configBoards{ID: 1, Name: "Reactive", ExtraMetrics: []string{"firstResponseHours"}}
+	stats, err := collectResponseStats(context.Background(), client, config{}, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stats["firstResponseHours"], (2.0+10+6+20)/4; got != want {
+		t.Errorf("firstResponseHours = %v, want %v", got, want)
+	}
+}
+
+func TestWriteExtraStatsClearsRewrittenRow(t *testing.T) {
+	sheet, err := xlsx.NewFile().AddSheet("Reactive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet.AddRow().AddCell().SetValue("Date")
+	row := sheet.AddRow()
+	row.AddCell().SetValue(testNow)
+
+	b := configBoards{Name: "Reactive", ExtraMetrics: []string{"firstResponseHours", "closed"}}
+	writeExtraStats(sheet, row, b, extraStats{"firstResponseHours": 2, "closed": 5})
+	// The second run of the day has no closed tickets to report
+	writeExtraStats(sheet, row, b, extraStats{"firstResponseHours": 3})
+
+	for i, m := range extraMetrics {
+		col := statCount + 1 + i
+		if col >= len(row.Cells) {
+			break
+		}
+		want := ""
+		if m.Key == "firstResponseHours" {
+			want = "3"
+		}
+		if got := row.Cells[col].Value; got != want {
+			t.Errorf("%s = %q, want %q", m.Key, got, want)
+		}
+	}
+}
@@ -1,15 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"net/http"
 
-	"github.com/simononebyte/restup"
+	"github.com/simononebyte/scorecard/psa"
 )
 
 // RMMClient encapsulates the RMM API Client
 type RMMClient struct {
-	restup        *restup.RestUp
+	transport     psa.Transport
+	retry         psa.RetryPolicy
 	reactiveSites []rmmSite
 }
 
@@ -18,10 +20,30 @@ type rmmSite struct {
 	SiteCode string
 }
 
+const defaultRMMBase = "https://api.itsupport247.net/reporting/v1/"
+
 // NewRMMClient ...
 func NewRMMClient(c config) *RMMClient {
-	rmm := RMMClient{}
-	rmm.restup = restup.NewRestUp("https://api.itsupport247.net/reporting/v1/", c.Continuum)
+	if c.rmmTransport != nil {
+		return NewRMMClientWithTransport(c, c.rmmTransport)
+	}
+	return NewRMMClientWithTransport(c, newRMMTransport(c))
+}
+
+func newRMMTransport(c config) *psa.HTTPTransport {
+	base := c.ContinuumBase
+	if base == "" {
+		base = defaultRMMBase
+	}
+	t := psa.NewHTTPTransport(base, c.Continuum)
+	t.OnRequest = logRequest("rmm")
+	return t
+}
+
+// NewRMMClientWithTransport creates an RMMClient that sends its requests
+// through t, e.g. to an rmmtest.Server
+func NewRMMClientWithTransport(c config, t psa.Transport) *RMMClient {
+	rmm := RMMClient{retry: c.Retry.policy(), transport: t}
 	for _, v := range c.ReactiveSites {
 		rmm.reactiveSites = append(rmm.reactiveSites, rmmSite{v.Name, v.SiteCode})
 	}
@@ -65,43 +87,39 @@ type RMMDevice struct {
 	MmemoryTotal        string `json:"memoryTotal"`
 }
 
-// GetRMMStats ...
-func (rmm *RMMClient) GetRMMStats() RMMStats {
+// GetRMMStats counts the devices at TSC sites and all other sites
+func (rmm *RMMClient) GetRMMStats(ctx context.Context) (RMMStats, error) {
 
 	stats := RMMStats{}
 
-	fmt.Printf("RMM: Getting Sites: ")
-	sites, sitesErr := rmm.GetRMMSites()
+	sites, sitesErr := rmm.GetRMMSites(ctx)
 	if sitesErr != nil {
-		fmt.Printf("error: \n%s\n", sitesErr)
-		os.Exit(1)
+		return RMMStats{}, fmt.Errorf("error getting sites: %s", sitesErr)
 	}
-	fmt.Printf("%d returned\n", len(sites))
+	logger.Info("rmm sites", "count", len(sites))
 
-	fmt.Printf("RMM: Getting device counts: ")
 	for _, v := range sites {
-		devs, devsErr := rmm.GetRMMEndpoints(v.SiteCode)
+		devs, devsErr := rmm.GetRMMEndpoints(ctx, v.SiteCode)
 		if devsErr != nil {
-			fmt.Printf("error: %s\n%s\n", v.Name, devsErr)
-			os.Exit(1)
+			return RMMStats{}, fmt.Errorf("error getting devices for %s: %s", v.Name, devsErr)
 		}
-		fmt.Printf(".")
+		logger.Debug("rmm site devices", "site", v.Name, "devices", len(devs))
 		if rmm.IsTSCSite(v.Name) == true {
 			stats.TSCDevices += len(devs)
 			continue
 		}
 		stats.OtherDevices += len(devs)
 	}
-	fmt.Printf("\n")
-	return stats
+	logger.Info("rmm devices", "tsc", stats.TSCDevices, "other", stats.OtherDevices)
+	return stats, nil
 }
 
 // GetRMMSites ..
-func (rmm *RMMClient) GetRMMSites() ([]RMMSite, error) {
+func (rmm *RMMClient) GetRMMSites(ctx context.Context) ([]RMMSite, error) {
 
 	sites := []RMMSite{}
 
-	if err := rmm.restup.Get("sites", &sites); err != nil {
+	if err := rmm.get(ctx, "sites", &sites); err != nil {
 		return sites, err
 	}
 
@@ -109,18 +127,25 @@ func (rmm *RMMClient) GetRMMSites() ([]RMMSite, error) {
 }
 
 // GetRMMEndpoints ...
-func (rmm *RMMClient) GetRMMEndpoints(siteCode string) ([]RMMDevice, error) {
+func (rmm *RMMClient) GetRMMEndpoints(ctx context.Context, siteCode string) ([]RMMDevice, error) {
 
 	cmd := fmt.Sprintf("sites/%s/devices/", siteCode)
 	devices := []RMMDevice{}
 
-	if err := rmm.restup.Get(cmd, &devices); err != nil {
+	if err := rmm.get(ctx, cmd, &devices); err != nil {
 		return devices, err
 	}
 
 	return devices, nil
 }
 
+// get runs a GET request, retrying transient failures
+func (rmm *RMMClient) get(ctx context.Context, cmd string, v interface{}) error {
+	return rmm.retry.Do(ctx, "GET "+cmd, v, func(ctx context.Context, v interface{}) error {
+		return rmm.transport.Do(ctx, http.MethodGet, cmd, nil, v)
+	})
+}
+
 // IsTSCSite determines if site is a Technology Success Customer site
 func (rmm *RMMClient) IsTSCSite(site string) bool {
 	for _, v := range rmm.reactiveSites {
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testRMMKey = "key:secret"
+
+// newRMMServer serves the sites and devices of the Continuum reporting API,
+// answering 401 to any other key and 500 to the paths in fail
+func newRMMServer(fail ...string) *httptest.Server {
+	devices := map[string]string{
+		"TSC1":  `[{"machineID": "1"}, {"machineID": "2"}]`,
+		"TSC2":  `[{"machineID": "3"}]`,
+		"OTHR1": `[{"machineID": "4"}, {"machineID": "5"}, {"machineID": "6"}]`,
+		"EMPTY": `[]`,
+	}
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(testRMMKey))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != auth {
+			http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		for _, f := range fail {
+			if r.URL.Path == f {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/")
+		if path == "sites" {
+			w.Write([]byte(`[
+				{"name": "Acme Ltd", "siteCode": "TSC1"},
+				{"name": "Bravo Ltd", "siteCode": "TSC2"},
+				{"name": "Charlie Ltd", "siteCode": "OTHR1"},
+				{"name": "Delta Ltd", "siteCode": "EMPTY"}
+			]`))
+			return
+		}
+		code := strings.TrimSuffix(strings.TrimPrefix(path, "sites/"), "/devices/")
+		if d, ok := devices[code]; ok && strings.HasSuffix(path, "/devices/") {
+			w.Write([]byte(d))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+func TestGetRMMStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		reactive []string
+		fail     []string
+		want     RMMStats
+		wantErr  string
+	}{
+		{
+			name:     "tsc and other sites",
+			key:      testRMMKey,
+			reactive: []string{"Acme Ltd", "Bravo Ltd"},
+			want:     RMMStats{TSCDevices: 3, OtherDevices: 3},
+		},
+		{
+			name:     "site names must match exactly",
+			key:      testRMMKey,
+			reactive: []string{"acme ltd", "Delta Ltd"},
+			want:     RMMStats{TSCDevices: 0, OtherDevices: 6},
+		},
+		{
+			name: "no reactive sites",
+			key:  testRMMKey,
+			want: RMMStats{OtherDevices: 6},
+		},
+		{
+			name:     "sites fail",
+			key:      testRMMKey,
+			reactive: []string{"Acme Ltd"},
+			fail:     []string{"/sites"},
+			wantErr:  "error getting sites",
+		},
+		{
+			name:     "one site's devices fail",
+			key:      testRMMKey,
+			reactive: []string{"Acme Ltd"},
+			fail:     []string{"/sites/OTHR1/devices/"},
+			wantErr:  "error getting devices for Charlie Ltd",
+		},
+		{
+			name:     "bad api key",
+			key:      "key:wrong",
+			reactive: []string{"Acme Ltd"},
+			wantErr:  "401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRMMServer(tt.fail...)
+			defer s.Close()
+
+			c := config{Continuum: tt.key, ContinuumBase: s.URL + "/"}
+			c.Retry = configRetry{MaxAttempts: 2, InitialBackoff: "1ms", MaxBackoff: "1ms"}
+			for _, name := range tt.reactive {
+				c.ReactiveSites = append(c.ReactiveSites, configSite{Name: name})
+			}
+
+			stats, err := NewRMMClient(c).GetRMMStats(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+			if stats != tt.want {
+				t.Errorf("got %+v, want %+v", stats, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Package rmmtest provides an in-process fake of the Continuum reporting API
+// for testing the RMM client without network access.
+package rmmtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Site is a site as returned by /sites
+type Site struct {
+	Name     string `json:"name"`
+	SiteCode string `json:"siteCode"`
+}
+
+// Device is a device as returned by /sites/{code}/devices/. Only the fields
+// the scorecard reads are modelled.
+type Device struct {
+	MachineID    string `json:"machineID"`
+	MachineName  string `json:"machineName"`
+	FriendlyName string `json:"friendlyName"`
+	AssetType    string `json:"assetType"`
+	SiteCode     string `json:"siteCode"`
+	SiteName     string `json:"siteName"`
+}
+
+// Server is a fake Continuum reporting API. Sites lists the sites returned by
+// /sites and Devices the devices for each site code. Errors makes a path,
+// e.g. "/sites" or "/sites/ABC/devices/", fail with the given status code.
+//
+// Records may be changed between requests by holding the lock.
+type Server struct {
+	sync.Mutex
+
+	Sites   []Site
+	Devices map[string][]Device
+	Errors  map[string]int
+
+	// Requests records the path of each request received
+	Requests []string
+
+	server *httptest.Server
+}
+
+// NewServer starts a Server, which must be closed when finished with
+func NewServer() *Server {
+	s := &Server{
+		Devices: make(map[string][]Device),
+		Errors:  make(map[string]int),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// NewFixtureServer starts a Server holding Fixture
+func NewFixtureServer() *Server {
+	s := NewServer()
+	s.Sites, s.Devices = Fixture()
+	return s
+}
+
+// Fixture is two TSC sites and one other site, with 3, 2 and 4 devices
+func Fixture() ([]Site, map[string][]Device) {
+	sites := []Site{
+		{Name: "True Methods Reactive Client", SiteCode: "TMRC"},
+		{Name: "Acme Ltd", SiteCode: "ACME"},
+		{Name: "Widget Co", SiteCode: "WIDG"},
+	}
+	devices := map[string][]Device{}
+	add := func(site Site, names ...string) {
+		for _, n := range names {
+			devices[site.SiteCode] = append(devices[site.SiteCode], Device{
+				MachineID:    site.SiteCode + "-" + n,
+				MachineName:  n,
+				FriendlyName: n,
+				AssetType:    "Desktop",
+				SiteCode:     site.SiteCode,
+				SiteName:     site.Name,
+			})
+		}
+	}
+	add(sites[0], "PC01", "PC02", "PC03")
+	add(sites[1], "SRV01", "PC01")
+	add(sites[2], "PC01", "PC02", "PC03", "LT01")
+	return sites, devices
+}
+
+// URL is the API base of the server
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.Requests = append(s.Requests, r.URL.Path)
+
+	if status, ok := s.Errors[r.URL.Path]; ok {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "sites":
+		writeJSON(w, s.Sites)
+	case len(parts) == 3 && parts[0] == "sites" && parts[2] == "devices":
+		devices, ok := s.Devices[parts[1]]
+		if !ok && !s.hasSite(parts[1]) {
+			http.NotFound(w, r)
+			return
+		}
+		if devices == nil {
+			devices = []Device{}
+		}
+		writeJSON(w, devices)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) hasSite(code string) bool {
+	for _, site := range s.Sites {
+		if site.SiteCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
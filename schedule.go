@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCron      = "0 10 * * 1"
+	defaultStateFile = "scorecard.state.json"
+	defaultLockFile  = "scorecard.lock"
+
+	// staleLockAge is how old a lock file must be before it is assumed to
+	// have been left behind by a run that died
+	staleLockAge = 6 * time.Hour
+
+	// schedulerPoll caps how long the scheduler sleeps before checking the
+	// clock again, so a machine waking from sleep is noticed promptly
+	schedulerPoll = time.Minute
+
+	// A failed run is retried after retryBackoff, doubling with each failure
+	// up to maxRetryBackoff, until it succeeds
+	retryBackoff    = time.Minute
+	maxRetryBackoff = time.Hour
+)
+
+type configSchedule struct {
+	Cron      string `json:"cron"`
+	Timezone  string `json:"timezone"`
+	StateFile string `json:"state_file"`
+	LockFile  string `json:"lock_file"`
+}
+
+func (s configSchedule) cron() string {
+	if s.Cron == "" {
+		return defaultCron
+	}
+	return s.Cron
+}
+
+func (s configSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s configSchedule) stateFile() string {
+	if s.StateFile == "" {
+		return defaultStateFile
+	}
+	return s.StateFile
+}
+
+func (s configSchedule) lockFile() string {
+	if s.LockFile == "" {
+		return defaultLockFile
+	}
+	return s.LockFile
+}
+
+// scheduleState is persisted between runs so missed runs can be caught up
+type scheduleState struct {
+	LastRun time.Time `json:"last_run"`
+	Status  string    `json:"status"`
+}
+
+// runRecord is logged for each scheduled run
+type runRecord struct {
+	Time      time.Time
+	Event     string
+	Scheduled time.Time
+	CatchUp   bool
+	Status    string
+	Duration  string
+	Error     string
+}
+
+// runScheduler runs forever, collecting and saving stats each time the cron
+// schedule falls due. If the machine was asleep or the process was not
+// running at the scheduled time, the missed run is made as soon as possible.
+// On first start the most recent scheduled time is treated as missed. A run
+// that fails is retried with backoff, the last run only moving on once it
+// succeeds.
+func runScheduler(ctx context.Context, c config) error {
+	sched, err := parseCron(c.Schedule.cron())
+	if err != nil {
+		return err
+	}
+	loc, err := c.Schedule.location()
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %s", err)
+	}
+
+	state, err := readScheduleState(c.Schedule.stateFile())
+	if err != nil {
+		return err
+	}
+	if state.LastRun.IsZero() {
+		// First start, catch up the most recent scheduled run
+		state.LastRun = time.Now()
+		if last, ok := sched.prev(state.LastRun.In(loc)); ok {
+			state.LastRun = last.Add(-time.Minute)
+		}
+	}
+
+	logRun(runRecord{Time: time.Now(), Event: "start",
+		Scheduled: sched.next(state.LastRun.In(loc)), Status: "waiting"})
+
+	failures := 0
+	var retryAt time.Time
+	for {
+		due := sched.next(state.LastRun.In(loc))
+		if retryAt.After(due) {
+			due = retryAt
+		}
+		now := time.Now()
+		if due.After(now) {
+			wait := due.Sub(now)
+			if wait > schedulerPoll {
+				wait = schedulerPoll
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil
+			}
+			continue
+		}
+
+		record := runScheduled(ctx, c, due)
+		logRun(record)
+
+		state.Status = record.Status
+		if record.Status == "ok" {
+			state.LastRun = record.Time
+			failures = 0
+			retryAt = time.Time{}
+		} else {
+			failures++
+			retryAt = record.Time.Add(retryWait(failures))
+			logRun(runRecord{Time: time.Now(), Event: "retry", Scheduled: retryAt, Status: "waiting"})
+		}
+		if err := writeScheduleState(c.Schedule.stateFile(), state); err != nil {
+			logRun(runRecord{Time: time.Now(), Event: "state", Status: "error", Error: err.Error()})
+		}
+	}
+}
+
+// retryWait is how long to wait before retrying after the given number of
+// consecutive failures
+func retryWait(failures int) time.Duration {
+	wait := retryBackoff
+	for i := 1; i < failures && wait < maxRetryBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return wait
+}
+
+func runScheduled(ctx context.Context, c config, due time.Time) (record runRecord) {
+	start := time.Now()
+	record = runRecord{
+		Event:     "run",
+		Scheduled: due,
+		CatchUp:   start.Sub(due) > schedulerPoll,
+		Status:    "ok",
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			record.Status = "error"
+			record.Error = fmt.Sprintf("panic: %v", r)
+		}
+		record.Time = time.Now()
+		record.Duration = record.Time.Sub(start).Round(time.Millisecond).String()
+	}()
+
+	if err := runBatch(ctx, c); err != nil {
+		record.Status = "error"
+		record.Error = err.Error()
+	}
+	return record
+}
+
+func logRun(r runRecord) {
+	kv := []interface{}{"event", r.Event, "scheduled", r.Scheduled, "status", r.Status}
+	if r.Event == "run" {
+		kv = append(kv, "catch_up", r.CatchUp, "duration", r.Duration)
+	}
+	if r.Error != "" {
+		kv = append(kv, "error", r.Error)
+		logger.Error("schedule", kv...)
+		return
+	}
+	logger.Info("schedule", kv...)
+}
+
+func readScheduleState(path string) (scheduleState, error) {
+	state := scheduleState{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("error reading %s: %s", path, err)
+	}
+	return state, nil
+}
+
+func writeScheduleState(path string, state scheduleState) error {
+	data, err := json.MarshalIndent(state, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// runLock prevents two collections running at the same time
+type runLock struct {
+	path string
+}
+
+func acquireRunLock(path string) (*runLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		info, statErr := os.Stat(path)
+		if statErr != nil || time.Since(info.ModTime()) < staleLockAge {
+			owner, _ := ioutil.ReadFile(path)
+			return nil, fmt.Errorf("another run is in progress (%s held by %s)",
+				path, strings.TrimSpace(string(owner)))
+		}
+		// Left behind by a run that died, take it over
+		os.Remove(path)
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating lock file: %s", err)
+	}
+	fmt.Fprintf(f, "pid %d at %s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	f.Close()
+	return &runLock{path: path}, nil
+}
+
+func (l *runLock) release() {
+	os.Remove(l.path)
+}
+
+// cronSchedule is a parsed five field cron expression of minute, hour,
+// day of month, month and day of week
+type cronSchedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, Sunday = 0 or 7
+}
+
+// parseCron parses a standard five field cron expression. Each field accepts
+// *, single values, ranges (1-5), lists (1,3,5) and steps (*/15, 1-5/2).
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron schedule %q: expected 5 fields", spec)
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule %q: %s", spec, err)
+		}
+		bits[i] = b
+	}
+
+	// Fold Sunday = 7 onto Sunday = 0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, r cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = s
+			part = part[:i]
+		}
+
+		lo, hi := r.min, r.max
+		if part != "*" {
+			var err error
+			if i := strings.Index(part, "-"); i >= 0 {
+				lo, err = strconv.Atoi(part[:i])
+				if err == nil {
+					hi, err = strconv.Atoi(part[i+1:])
+				}
+			} else {
+				lo, err = strconv.Atoi(part)
+				hi = lo
+				if step > 1 {
+					hi = r.max
+				}
+			}
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+		}
+		if lo < r.min || hi > r.max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, r.min, r.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// next returns the first scheduled time strictly after t, in t's location
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return limit
+}
+
+// prevLookback are the windows searched for the previous scheduled time, short
+// first so frequent schedules are not walked a minute at a time for a year
+var prevLookback = []time.Duration{24 * time.Hour, 32 * 24 * time.Hour, 366 * 24 * time.Hour}
+
+// prev returns the last scheduled time at or before t, if there was one in
+// the past year
+func (s *cronSchedule) prev(t time.Time) (time.Time, bool) {
+	for _, window := range prevLookback {
+		last, found := time.Time{}, false
+		for n := s.next(t.Add(-window)); !n.After(t); n = s.next(n) {
+			last, found = n, true
+		}
+		if found {
+			return last, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dayMatches follows cron's rule that when both day fields are restricted a
+// day matching either of them is scheduled
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
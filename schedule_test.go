@@ -0,0 +1,187 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		minute  []int
+		dow     []int
+		wantErr bool
+	}{
+		{spec: "0 10 * * 1", minute: []int{0}, dow: []int{1}},
+		{spec: "*/15 * * * *", minute: []int{0, 15, 30, 45}, dow: []int{0, 1, 2, 3, 4, 5, 6}},
+		{spec: "5,35 9-17 * * 1-5", minute: []int{5, 35}, dow: []int{1, 2, 3, 4, 5}},
+		{spec: "10-50/20 * * * 1-5/2", minute: []int{10, 30, 50}, dow: []int{1, 3, 5}},
+		{spec: "50/5 * * * 7", minute: []int{50, 55}, dow: []int{0}},
+		{spec: "0 0 * * 0,7", minute: []int{0}, dow: []int{0}},
+		{spec: "0 10 * *", wantErr: true},
+		{spec: "0 10 * * 1 2", wantErr: true},
+		{spec: "60 10 * * 1", wantErr: true},
+		{spec: "0 24 * * 1", wantErr: true},
+		{spec: "0 10 0 * *", wantErr: true},
+		{spec: "0 10 * 13 *", wantErr: true},
+		{spec: "0 10 * * 8", wantErr: true},
+		{spec: "5-1 10 * * *", wantErr: true},
+		{spec: "*/0 10 * * *", wantErr: true},
+		{spec: "a 10 * * *", wantErr: true},
+	}
+
+	bits := func(values []int) uint64 {
+		var b uint64
+		for _, v := range values {
+			b |= 1 << uint(v)
+		}
+		return b
+	}
+
+	for _, tt := range tests {
+		s, err := parseCron(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q parsed, want an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", tt.spec, err)
+			continue
+		}
+		if s.minute != bits(tt.minute) {
+			t.Errorf("%q minutes = %b, want %v", tt.spec, s.minute, tt.minute)
+		}
+		if s.dow != bits(tt.dow) {
+			t.Errorf("%q days of week = %b, want %v", tt.spec, s.dow, tt.dow)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+	at := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, london)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		// Monday 9 March 2020
+		{"0 10 * * 1", "2020-03-09 09:00", "2020-03-09 10:00"},
+		{"0 10 * * 1", "2020-03-09 10:00", "2020-03-16 10:00"},
+		{"0 10 * * 1", "2020-03-09 10:00:30", "2020-03-16 10:00"},
+		{"*/15 * * * *", "2020-03-09 10:01", "2020-03-09 10:15"},
+		{"30 17 * * 5", "2020-03-09 10:00", "2020-03-13 17:30"},
+		{"0 0 1 * *", "2020-03-09 10:00", "2020-04-01 00:00"},
+		{"0 9 31 * *", "2020-04-01 00:00", "2020-05-31 09:00"},
+		{"0 0 29 2 *", "2020-03-01 00:00", "2024-02-29 00:00"},
+		{"0 6 * 1 *", "2020-12-31 23:59", "2021-01-01 06:00"},
+		// Either day field matches when both are restricted
+		{"0 8 15 * 1", "2020-03-10 00:00", "2020-03-15 08:00"},
+		{"0 8 15 * 1", "2020-03-15 09:00", "2020-03-16 08:00"},
+		// The hour skipped when the clocks go forward on 29 March 2020
+		{"30 1 * * *", "2020-03-28 02:00", "2020-03-30 01:30"},
+	}
+
+	for _, tt := range tests {
+		s, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from := at(tt.from[:16])
+		if len(tt.from) > 16 {
+			from = from.Add(30 * time.Second)
+		}
+		if got := s.next(from); !got.Equal(at(tt.want)) {
+			t.Errorf("%q after %s = %s, want %s", tt.spec, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronPrev(t *testing.T) {
+	from := time.Date(2020, 3, 11, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+		ok   bool
+	}{
+		{"0 10 * * 1", time.Date(2020, 3, 9, 10, 0, 0, 0, time.UTC), true},
+		{"0 12 * * 3", from, true},
+		{"* * * * *", from, true},
+		{"0 0 1 1 *", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 30 2 *", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		s, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := s.prev(from)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("%q before %s = %s %v, want %s %v", tt.spec, from, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCronDayMatches(t *testing.T) {
+	// Sunday 15 March 2020 and Monday 16 March 2020
+	sunday15 := time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)
+	monday16 := sunday15.AddDate(0, 0, 1)
+
+	tests := []struct {
+		spec string
+		day  time.Time
+		want bool
+	}{
+		{"0 0 * * *", sunday15, true},
+		{"0 0 15 * *", sunday15, true},
+		{"0 0 15 * *", monday16, false},
+		{"0 0 * * 1", monday16, true},
+		{"0 0 * * 1", sunday15, false},
+		{"0 0 * * 7", sunday15, true},
+		{"0 0 15 * 1", sunday15, true},
+		{"0 0 15 * 1", monday16, true},
+		{"0 0 1 * 2", monday16, false},
+	}
+
+	for _, tt := range tests {
+		s, err := parseCron(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.dayMatches(tt.day); got != tt.want {
+			t.Errorf("%q on %s = %v, want %v", tt.spec, tt.day.Format("Mon 2 Jan"), got, tt.want)
+		}
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, maxRetryBackoff},
+		{100, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := retryWait(tt.failures); got != tt.want {
+			t.Errorf("retryWait(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
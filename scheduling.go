@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// scheduleFields are the ticket fields the schedule stats need
+var scheduleFields = []string{"id", "_info/lastUpdated"}
+
+// collectScheduleStats works out, from the schedule entries of the open
+// tickets:
+//
+// notScheduled, the open tickets with no schedule entry
+//
+// scheduleOverdue, the open tickets whose last schedule entry has ended
+// without the ticket being updated since. A ticket that has been rescheduled,
+// with an entry still to come that is not done, is not overdue.
+func collectScheduleStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	tickets, err := client.GetOpenTicketsByBoardIDContext(ctx, board.ID, scheduleFields...)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(tickets))
+	for i, t := range tickets {
+		ids[i] = t.ID
+	}
+	entries, err := client.GetTicketScheduleEntriesContext(ctx, ids, "id", "objectId", "dateEnd", "doneFlag")
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock()
+	scheduled := map[int]bool{}
+	pending := map[int]bool{}
+	lastEnded := map[int]time.Time{}
+	for _, e := range entries {
+		scheduled[e.ObjectID] = true
+		if !e.DateEnd.Before(now) {
+			if !e.DoneFlag {
+				pending[e.ObjectID] = true
+			}
+			continue
+		}
+		if e.DateEnd.After(lastEnded[e.ObjectID]) {
+			lastEnded[e.ObjectID] = e.DateEnd
+		}
+	}
+
+	notScheduled, overdue := 0, 0
+	for _, t := range tickets {
+		if !scheduled[t.ID] {
+			notScheduled++
+			continue
+		}
+		if end, ok := lastEnded[t.ID]; ok && !pending[t.ID] && t.Info.LastUpdated.Before(end) {
+			overdue++
+		}
+	}
+
+	stats := extraStats{}
+	if board.hasExtra("notScheduled") {
+		stats["notScheduled"] = float64(notScheduled)
+	}
+	if board.hasExtra("scheduleOverdue") {
+		stats["scheduleOverdue"] = float64(overdue)
+	}
+	return stats, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/simononebyte/scorecard/psa/psatest"
+)
+
+func TestCollectScheduleStats(t *testing.T) {
+	defer func(c func() time.Time) { clock = c }(clock)
+	clock = func() time.Time { return testNow }
+
+	s := psatest.NewServer()
+	defer s.Close()
+	board := psa.Board{ID: 1, Name: "Reactive"}
+	s.Boards = []psa.Board{board}
+
+	days := func(n int) time.Time { return testNow.AddDate(0, 0, n) }
+	ticket := func(id int, updated int) psa.Ticket {
+		return psa.Ticket{ID: id, Board: board, Info: psa.Info{LastUpdated: days(updated)}}
+	}
+	entry := func(id int, ticketID int, end int, done bool) psa.ScheduleEntry {
+		return psa.ScheduleEntry{
+			ID: id, ObjectID: ticketID, Type: psa.ScheduleType{Identifier: psa.ScheduleTypeServiceTicket},
+			DateStart: days(end).Add(-time.Hour), DateEnd: days(end), DoneFlag: done,
+		}
+	}
+	s.Tickets = []psa.Ticket{
+		ticket(1, -5), // not scheduled
+		ticket(2, -5), // visit passed without an update, overdue
+		ticket(3, -1), // updated since the visit
+		ticket(4, -5), // rescheduled for next week
+		ticket(5, -5), // next week's visit already marked done, overdue
+		ticket(6, -5), // only scheduled for the future
+	}
+	s.ScheduleEntries = []psa.ScheduleEntry{
+		entry(1, 2, -3, false),
+		entry(2, 3, -3, true),
+		entry(3, 4, -3, false),
+		entry(4, 4, 7, false),
+		entry(5, 5, -3, false),
+		entry(6, 5, 7, true),
+		entry(7, 6, 2, false),
+	}
+
+	c := s.Config()
+	c.Clock = clock
+	client, err := psa.NewClient(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := configBoards{ID: 1, Name: "Reactive", ExtraMetrics: []string{"notScheduled", "scheduleOverdue"}}
+	stats, err := collectScheduleStats(context.Background(), client, config{}, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats["notScheduled"] != 1 {
+		t.Errorf("notScheduled = %v, want 1", stats["notScheduled"])
+	}
+	if stats["scheduleOverdue"] != 2 {
+		t.Errorf("scheduleOverdue = %v, want 2", stats["scheduleOverdue"])
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
 	"time"
 
@@ -14,12 +16,75 @@ import (
 )
 
 type config struct {
-	Continuum     string         `json:"rmm_key"`
-	ConnectWise   psa.Config     `json:"psa_key"`
-	Boards        []configBoards `json:"psa_boards"`
-	Excludes      psa.Excludes   `json:"psa_excludes"`
-	ReactiveSites []configSite   `json:"reactive_endpoints"`
-	StatsFile     string         `json:"stats_file"`
+	Continuum     string          `json:"rmm_key"`
+	ContinuumBase string          `json:"rmm_api_base"`
+	ConnectWise   psa.Config      `json:"psa_key"`
+	Boards        []configBoards  `json:"psa_boards"`
+	Excludes      psa.Excludes    `json:"psa_excludes"`
+	ReactiveSites []configSite    `json:"reactive_endpoints"`
+	StatsFile     string          `json:"stats_file"`
+	StatsBackups  *int            `json:"stats_backups"`
+	Schedule      configSchedule  `json:"schedule"`
+	Dashboard     configDash      `json:"dashboard"`
+	Metrics       configMetrics   `json:"metrics"`
+	Retry         configRetry     `json:"retry"`
+	Log           configLog       `json:"log"`
+	Companies     configCompanies `json:"companies"`
+	Sources       configSources   `json:"sources"`
+	Teams         []configTeam    `json:"psa_teams"`
+	Staff         configStaff     `json:"staff"`
+
+	// ClosedStatuses are the status names that close a ticket, used to
+	// spot tickets being reopened
+	ClosedStatuses []string `json:"closed_statuses"`
+
+	// AutomationMembers are the identifiers of the API and integration
+	// members, whose notes and changes are not counted as an update by
+	// noHumanUpdate7
+	AutomationMembers []string `json:"automation_members"`
+
+	// StatusAgeing counts the tickets left too long in a status
+	StatusAgeing configStatusAgeing `json:"status_ageing"`
+
+	// rmmTransport replaces the Continuum API when recording or replaying
+	rmmTransport psa.Transport
+}
+
+// configRetry is the retry policy for both the PSA and RMM APIs. Durations
+// are strings such as "30s", any left empty use the psa package defaults.
+type configRetry struct {
+	MaxAttempts    int    `json:"max_attempts"`
+	InitialBackoff string `json:"initial_backoff"`
+	MaxBackoff     string `json:"max_backoff"`
+	RequestTimeout string `json:"request_timeout"`
+	Deadline       string `json:"deadline"`
+}
+
+func (r configRetry) validate() error {
+	for _, d := range []string{r.InitialBackoff, r.MaxBackoff, r.RequestTimeout, r.Deadline} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("invalid retry setting: %s", err)
+		}
+	}
+	return nil
+}
+
+func (r configRetry) policy() psa.RetryPolicy {
+	duration := func(s string) time.Duration {
+		d, _ := time.ParseDuration(s)
+		return d
+	}
+	return psa.RetryPolicy{
+		MaxAttempts:    r.MaxAttempts,
+		InitialBackoff: duration(r.InitialBackoff),
+		MaxBackoff:     duration(r.MaxBackoff),
+		RequestTimeout: duration(r.RequestTimeout),
+		Deadline:       duration(r.Deadline),
+		Logf:           logger.Warnf,
+	}
 }
 
 type configSite struct {
@@ -28,9 +93,23 @@ type configSite struct {
 }
 
 type configBoards struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	Worksheet string `json:"worksheet"`
+	ID        int                   `json:"id"`
+	Name      string                `json:"name"`
+	Worksheet string                `json:"worksheet"`
+	Goals     map[string]configGoal `json:"goals"`
+	Details   []string              `json:"details"`
+
+	// ExtraMetrics lists the extraMetrics keys collected for the board
+	ExtraMetrics []string `json:"extra_metrics"`
+}
+
+func (b configBoards) hasDetail(metric string) bool {
+	for _, d := range b.Details {
+		if d == metric {
+			return true
+		}
+	}
+	return false
 }
 
 var excludeBoards = []string{
@@ -45,6 +124,55 @@ type boardStats struct {
 	older31     int
 	assigned    int
 	notAssigned int
+
+	// tickets behind each stat, keyed by metric
+	tickets boardTickets
+
+	// extra holds the stats for the board's extra_metrics
+	extra extraStats
+}
+
+type boardTickets map[string][]psa.Ticket
+
+// boardMetric names a board stat. boardMetrics is in worksheet column order.
+type boardMetric struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+var boardMetrics = []boardMetric{
+	{"open", "Open"},
+	{"new", "New"},
+	{"noUpdate7", "No Update in 7 days"},
+	{"older7", "Older 7 days"},
+	{"older31", "Older 31 days"},
+	{"assigned", "Assigned"},
+	{"notAssigned", "Not Assigned"},
+}
+
+// set sets the stat for a boardMetrics key
+func (s *boardStats) set(key string, v int) {
+	switch key {
+	case "open":
+		s.open = v
+	case "new":
+		s.new = v
+	case "noUpdate7":
+		s.noUpdate7 = v
+	case "older7":
+		s.older7 = v
+	case "older31":
+		s.older31 = v
+	case "assigned":
+		s.assigned = v
+	case "notAssigned":
+		s.notAssigned = v
+	}
+}
+
+// values returns the stats in boardMetrics order
+func (s boardStats) values() []int {
+	return []int{s.open, s.new, s.noUpdate7, s.older7, s.older31, s.assigned, s.notAssigned}
 }
 
 type boardStatsMap map[string]boardStats
@@ -60,49 +188,189 @@ func (m boardStatsMap) getKeys() []string {
 
 const statCount = 7
 
+// clock is the time the stats are collected for, pinned to the time of the
+// recording when replaying
+var clock = time.Now
+
 func main() {
 
 	batchFlag := flag.Bool("batch", false, "Run in batch mode")
+	recordFlag := flag.String("record", "", "Record all API traffic to this directory")
+	replayFlag := flag.String("replay", "", "Replay API traffic recorded in this directory")
+	dryRunFlag := flag.Bool("dry-run", false, "Show the workbook changes without saving them")
+	printOnlyFlag := flag.Bool("print-only", false, "Dry run printing the API requests without sending them")
 	flag.Parse()
-	fmt.Println("Batch? ", *batchFlag)
-	if isBatch(batchFlag) {
-		fmt.Println("Running in Batch mode")
-	} else {
-		fmt.Println("Running in interractive mode")
-	}
+	// Cancel any API requests in flight on Ctrl+C. The handler is removed
+	// after the first signal so a second Ctrl+C quits straight away.
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		signal.Stop(interrupt)
+		cancel()
+	}()
+
 	c, configErr := readConfig()
 	if configErr != nil {
-		fmt.Printf("error reading config: \n%s\n", configErr)
-		os.Exit(1)
+		fatal("error reading config", configErr)
 	}
-
-	psa, err := psa.NewClient(c.ConnectWise, excludeBoards)
-	if err != nil {
-		panic(err)
+	if err := setupLogging(&c); err != nil {
+		fatal("error setting up logging", err)
 	}
+	if err := setupRecording(&c, *recordFlag, *replayFlag); err != nil {
+		fatal("error setting up recording", err)
+	}
+	logger.Debug("starting", "command", flag.Arg(0), "batch", isBatch(batchFlag),
+		"dry_run", *dryRunFlag, "print_only", *printOnlyFlag)
 
-	stats := boardStatsMap{}
+	if *dryRunFlag || *printOnlyFlag {
+		if err := runDryRun(ctx, c, *printOnlyFlag); err != nil {
+			fatal("error", err)
+		}
+		os.Exit(0)
+	}
 
-	for _, board := range c.Boards {
-		stats[board.Name] = getStatsforBoard(psa, board.ID)
+	switch flag.Arg(0) {
+	case "dashboard":
+		if err := runDashboard(ctx, c); err != nil {
+			fatal("error running dashboard", err)
+		}
+		os.Exit(0)
+	case "show":
+		if err := runShow(ctx, c, flag.Args()[1:]); err != nil {
+			fatal("error", err)
+		}
+		os.Exit(0)
+	case "schedule", "serve":
+		if err := runScheduler(ctx, c); err != nil {
+			fatal("error running scheduler", err)
+		}
+		os.Exit(0)
 	}
 
-	// TODO
-	// Wrap in if clause - only print if in interactive mode
 	if isBatch(batchFlag) {
-		saveStats(c, stats)
+		if err := runBatch(ctx, c); err != nil {
+			fatal("error saving stats", err)
+		}
 		os.Exit(0)
 	}
 
 	// Interactive mode
+	stats, err := collectRunStats(ctx, c)
+	if err != nil {
+		fatal("error collecting stats", err)
+	}
 	printStats(c, stats)
+	waitForEnter(ctx)
 
 }
 
-func printStats(c config, stats boardStatsMap) {
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// runBatch collects the stats and saves them to the workbook. The run lock is
+// held throughout so a manual run cannot overlap with a scheduled one.
+func runBatch(ctx context.Context, c config) error {
+	lock, err := acquireRunLock(c.Schedule.lockFile())
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	stats, err := collectRunStats(ctx, c)
+	if err != nil {
+		return err
+	}
+	return saveStats(c, stats)
+}
+
+// runStats is everything a run collects. The board stats are always
+// collected, the others only when configured.
+type runStats struct {
+	boards    boardStatsMap
+	companies companyStats
+	sources   sourceStats
+	teams     teamStatsMap
+	staff     staffStats
+	ageing    statusAgeingStats
+}
+
+func collectRunStats(ctx context.Context, c config) (runStats, error) {
+	var stats runStats
+	var err error
+
+	if stats.boards, err = collectStats(ctx, c); err != nil {
+		return stats, err
+	}
+	if stats.companies, err = collectCompanyStats(ctx, c); err != nil {
+		return stats, fmt.Errorf("companies: %s", err)
+	}
+	if stats.sources, err = collectSourceStats(ctx, c); err != nil {
+		return stats, fmt.Errorf("sources: %s", err)
+	}
+	if stats.teams, err = collectTeamStats(ctx, c); err != nil {
+		return stats, err
+	}
+	if stats.staff, err = collectStaffStats(ctx, c); err != nil {
+		return stats, fmt.Errorf("staff: %s", err)
+	}
+	if stats.ageing, err = collectStatusAgeingStats(ctx, c); err != nil {
+		return stats, fmt.Errorf("status ageing: %s", err)
+	}
+	return stats, nil
+}
+
+// writeRunStats updates the workbook in memory with everything collected
+func writeRunStats(f *xlsx.File, c config, stats runStats) error {
+	if err := writeStats(f, c, stats.boards); err != nil {
+		return err
+	}
+	if err := writeCompanyStats(f, c, stats.companies); err != nil {
+		return err
+	}
+	if err := writeSourceStats(f, c, stats.sources); err != nil {
+		return err
+	}
+	if err := writeTeamStats(f, c, stats.teams); err != nil {
+		return err
+	}
+	if err := writeStaffStats(f, c, stats.staff); err != nil {
+		return err
+	}
+	return writeStatusAgeingStats(f, c, stats.ageing)
+}
+
+// collectStats gets the stats for every configured board
+func collectStats(ctx context.Context, c config) (boardStatsMap, error) {
+	psa, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := boardStatsMap{}
+
+	for _, board := range c.Boards {
+		stat, err := getStatsforBoard(ctx, psa, board)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		stat.extra, err = getExtraStatsForBoard(ctx, psa, c, board)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		stats[board.Name] = stat
+	}
+	return stats, nil
+}
+
+func printStats(c config, stats runStats) {
 
 	// boardWidth := maxStringLen(stats.)
-	for name, stat := range stats {
+	for name, stat := range stats.boards {
 		fmt.Println(name)
 		fmt.Printf("  Open                : %3d\n", stat.open)
 		fmt.Printf("  New                 : %3d\n", stat.new)
@@ -111,10 +379,34 @@ func printStats(c config, stats boardStatsMap) {
 		fmt.Printf("  Older 31 days       : %3d\n", stat.older31)
 		fmt.Printf("  Assigned            : %3d\n", stat.assigned)
 		fmt.Printf("  Not Assigned        : %3d\n", stat.notAssigned)
+		for _, m := range extraMetrics {
+			if v, ok := stat.extra[m.Key]; ok {
+				fmt.Printf("  %-20s: %3s%s\n", m.Label, formatStat(v), goalStatus(c, name, m.Key, v))
+			}
+		}
 		fmt.Println("---------------------------")
 	}
+	printTeamStats(c, stats.teams)
+	printStatusAgeingStats(c, stats.ageing)
+	printSourceStats(c, stats.sources)
+	printTopCompanies(c, stats.companies)
+	printStaffStats(c, stats.staff)
+}
+
+// waitForEnter keeps the window open until Enter is pressed or ctx is
+// cancelled
+func waitForEnter(ctx context.Context) {
 	fmt.Printf("\n\nPress Enter to close window")
-	bufio.NewReader(os.Stdin).ReadBytes('\n')
+	entered := make(chan struct{})
+	go func() {
+		bufio.NewReader(os.Stdin).ReadBytes('\n')
+		close(entered)
+	}()
+	select {
+	case <-entered:
+	case <-ctx.Done():
+		fmt.Println()
+	}
 }
 
 func rightPadString(s string, w int) string {
@@ -131,14 +423,25 @@ func isBatch(flag *bool) bool {
 	return flag != nil && *flag == true
 }
 
-func saveStats(c config, stats boardStatsMap) error {
+func saveStats(c config, stats runStats) error {
 
 	// open excel file
 	f, err := xlsx.OpenFile(c.StatsFile)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
+	if err := writeRunStats(f, c, stats); err != nil {
+		return err
+	}
+
+	return saveWorkbook(f, c.StatsFile, c.statsBackups())
+}
+
+// writeStats updates the workbook in memory with this week's stats
+func writeStats(f *xlsx.File, c config, stats boardStatsMap) error {
+
+	detailNames := detailSheetNames(c)
 	for _, board := range c.Boards {
 		sheet := getSheet(f, board.Worksheet)
 		if sheet == nil {
@@ -149,7 +452,7 @@ func saveStats(c config, stats boardStatsMap) error {
 		var row *xlsx.Row
 
 		if isLastRowToday(sheet) {
-			row = sheet.Rows[sheet.MaxRow-1]
+			row = sheet.Rows[len(sheet.Rows)-1]
 		} else {
 			row = sheet.AddRow()
 		}
@@ -160,7 +463,7 @@ func saveStats(c config, stats boardStatsMap) error {
 			}
 		}
 
-		row.Cells[0].SetValue(time.Now().UTC().Truncate(24 * time.Hour))
+		row.Cells[0].SetValue(clock().UTC().Truncate(24 * time.Hour))
 		row.Cells[1].SetValue(stat.open)
 		row.Cells[2].SetValue(stat.new)
 		row.Cells[3].SetValue(stat.noUpdate7)
@@ -168,15 +471,29 @@ func saveStats(c config, stats boardStatsMap) error {
 		row.Cells[5].SetValue(stat.older31)
 		row.Cells[6].SetValue(stat.assigned)
 		row.Cells[7].SetValue(stat.notAssigned)
+		writeExtraStats(sheet, row, board, stat.extra)
+
+		for _, metric := range board.Details {
+			name := detailNames[detailSheet{board.Name, metric}]
+			if err := saveDetailSheet(f, name, board, metric, stat.tickets[metric]); err != nil {
+				return err
+			}
+		}
 
 	}
 
-	return f.Save(c.StatsFile)
+	return nil
 }
 
 func isLastRowToday(sheet *xlsx.Sheet) bool {
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-	lastRow := sheet.Rows[sheet.MaxRow-1]
+	if len(sheet.Rows) == 0 {
+		return false
+	}
+	today := clock().UTC().Truncate(24 * time.Hour)
+	lastRow := sheet.Rows[len(sheet.Rows)-1]
+	if lastRow == nil || len(lastRow.Cells) == 0 {
+		return false
+	}
 	lastTime, _ := lastRow.Cells[0].GetTime(false)
 	return today == lastTime
 }
@@ -194,59 +511,101 @@ func getDDMMYYYYString(date time.Time) string {
 	return fmt.Sprintf("%d//%d//%d", date.Day(), date.Month(), date.Year())
 }
 
-func getStatsforBoard(psa *psa.Client, id int) boardStats {
-	stats := boardStats{}
+// ticketSummaryFields is aliased as the psa package is shadowed by the
+// client inside getStatsforBoard
+var ticketSummaryFields = psa.TicketSummaryFields
 
-	// open
-	openTickets, err := psa.GetOpenTicketsByBoardID(id)
-	if err != nil {
-		panic("")
-	}
-	stats.open = len(openTickets)
-
-	// new
-	newTickets, err := psa.GetNewTicketsByBoardID(id, 7)
-	if err != nil {
-		panic("")
-	}
-	stats.new = len(newTickets)
+// boardQuery fetches the tickets behind a board metric, or just counts them
+type boardQuery struct {
+	list  func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error)
+	count func(ctx context.Context, client *psa.Client, boardID int) (int, error)
+}
 
-	// noUpdate7
-	noUpdate7Tickets, err := psa.GetOpenTicketsByBoardIDNotUpdatedIn(id, 7)
-	if err != nil {
-		panic("")
-	}
-	stats.noUpdate7 = len(noUpdate7Tickets)
+var boardQueries = map[string]boardQuery{
+	"open": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenTicketsByBoardIDContext(ctx, boardID, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenTicketsByBoardIDContext(ctx, boardID)
+		},
+	},
+	"new": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetNewTicketsByBoardIDContext(ctx, boardID, 7, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountNewTicketsByBoardIDContext(ctx, boardID, 7)
+		},
+	},
+	"noUpdate7": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenTicketsByBoardIDNotUpdatedInContext(ctx, boardID, 7, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenTicketsByBoardIDNotUpdatedInContext(ctx, boardID, 7)
+		},
+	},
+	"older7": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 7, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 7)
+		},
+	},
+	"older31": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 31, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenTicketsByBoardIDOlderThanContext(ctx, boardID, 31)
+		},
+	},
+	"assigned": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenAssignedTicketsByBoardIDContext(ctx, boardID, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenAssignedTicketsByBoardIDContext(ctx, boardID)
+		},
+	},
+	"notAssigned": {
+		func(ctx context.Context, client *psa.Client, boardID int, fields []string) ([]psa.Ticket, error) {
+			return client.GetOpenNotAssignedTicketsByBoardIDContext(ctx, boardID, fields...)
+		},
+		func(ctx context.Context, client *psa.Client, boardID int) (int, error) {
+			return client.CountOpenNotAssignedTicketsByBoardIDContext(ctx, boardID)
+		},
+	},
+}
 
-	// older7
-	older7Tickets, err := psa.GetOpenTicketsByBoardIDOlderThan(id, 7)
-	if err != nil {
-		panic("")
-	}
-	stats.older7 = len(older7Tickets)
+// getStatsforBoard uses the count queries for each stat, except for metrics
+// with a detail worksheet where the tickets themselves are needed
+func getStatsforBoard(ctx context.Context, psa *psa.Client, board configBoards) (boardStats, error) {
+	stats := boardStats{tickets: boardTickets{}}
 
-	// older31
-	older31Tickets, err := psa.GetOpenTicketsByBoardIDOlderThan(id, 31)
-	if err != nil {
-		panic("")
-	}
-	stats.older31 = len(older31Tickets)
+	for _, m := range boardMetrics {
+		query := boardQueries[m.Key]
 
-	// assigned
-	assignedTickets, err := psa.GetOpenAssignedTicketsByBoardID(id)
-	if err != nil {
-		panic("")
-	}
-	stats.assigned = len(assignedTickets)
+		if !board.hasDetail(m.Key) {
+			n, err := query.count(ctx, psa, board.ID)
+			if err != nil {
+				return stats, err
+			}
+			stats.set(m.Key, n)
+			continue
+		}
 
-	// notAssigned
-	notAssignedTickets, err := psa.GetOpenNotAssignedTicketsByBoardID(id)
-	if err != nil {
-		panic("")
+		tickets, err := query.list(ctx, psa, board.ID, ticketSummaryFields)
+		if err != nil {
+			return stats, err
+		}
+		stats.tickets[m.Key] = tickets
+		stats.set(m.Key, len(tickets))
 	}
-	stats.notAssigned = len(notAssignedTickets)
 
-	return stats
+	return stats, nil
 }
 
 func usage() {
@@ -254,8 +613,28 @@ func usage() {
 	fmt.Println("")
 	fmt.Println("    scrorecard -batch")
 	fmt.Println("")
-	fmt.Println("    batch - Saves stats to the Excel spreadsheet specified")
-	fmt.Println("            in the config file")
+	fmt.Println("    scrorecard schedule")
+	fmt.Println("")
+	fmt.Println("    scrorecard dashboard")
+	fmt.Println("")
+	fmt.Println("    scrorecard show -board Reactive -metric older31")
+	fmt.Println("")
+	fmt.Println("    batch     - Saves stats to the Excel spreadsheet specified")
+	fmt.Println("                in the config file")
+	fmt.Println("    schedule  - Runs continuously, saving stats on the cron")
+	fmt.Println("                schedule specified in the config file")
+	fmt.Println("    dashboard - Serves the scorecard as a web page")
+	fmt.Println("    show      - Lists the tickets behind a board metric")
+	fmt.Println("")
+	fmt.Println("    -record dir - Saves every API request and response to dir")
+	fmt.Println("    -replay dir - Answers API requests from a recording in dir")
+	fmt.Println("    -dry-run    - Shows the cells a batch run would change, without")
+	fmt.Println("                  saving the workbook")
+	fmt.Println("    -print-only - Dry run that prints the API requests instead of")
+	fmt.Println("                  sending them")
+	fmt.Println("")
+	fmt.Println("    Diagnostics are logged to stderr, or the file set in the log")
+	fmt.Println("    section of the config file")
 	os.Exit(0)
 }
 
@@ -266,9 +645,27 @@ func readConfig() (config, error) {
 	defer f.Close()
 
 	d := json.NewDecoder(f)
-	err := d.Decode(&c)
+	if err := d.Decode(&c); err != nil {
+		return c, err
+	}
+	if err := c.Retry.validate(); err != nil {
+		return c, err
+	}
+	if err := c.Log.validate(); err != nil {
+		return c, err
+	}
+	if err := validateExtraMetrics(c.Boards); err != nil {
+		return c, err
+	}
+	if err := validateTeams(c.Teams); err != nil {
+		return c, err
+	}
+	if err := c.StatusAgeing.validate(); err != nil {
+		return c, err
+	}
+	c.ConnectWise.Retry = c.Retry.policy()
 
-	return c, err
+	return c, nil
 }
 
 func getMaxStringLen(list []string) int {
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/simononebyte/scorecard/psa/psatest"
+)
+
+var testNow = time.Date(2020, 3, 9, 12, 0, 0, 0, time.UTC)
+
+// newStatsServer serves a reactive board whose tickets land in known
+// board metrics, plus a ticket on another board that must never be counted
+func newStatsServer(t *testing.T) (*psatest.Server, *psa.Client) {
+	t.Helper()
+	s := psatest.NewServer()
+	reactive := psa.Board{ID: 1, Name: "Reactive"}
+	projects := psa.Board{ID: 2, Name: "Projects"}
+	s.Boards = []psa.Board{reactive, projects}
+
+	days := func(n int) time.Time { return testNow.AddDate(0, 0, -n) }
+	ticket := func(id int, board psa.Board, closed bool, entered, updated int, resources string) psa.Ticket {
+		return psa.Ticket{
+			ID: id, Board: board, ClosedFlag: closed, Resources: resources,
+			DateEntered: days(entered), Info: psa.Info{LastUpdated: days(updated)},
+		}
+	}
+	s.Tickets = []psa.Ticket{
+		ticket(1, reactive, false, 1, 0, "jbloggs"),
+		ticket(2, reactive, false, 3, 2, ""),
+		ticket(3, reactive, false, 10, 9, "asmith"),
+		ticket(4, reactive, false, 40, 1, ""),
+		ticket(5, reactive, true, 2, 1, "jbloggs"),
+		ticket(6, projects, false, 50, 50, ""),
+	}
+
+	c := s.Config()
+	c.Clock = func() time.Time { return testNow }
+	client, err := psa.NewClient(c, nil)
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func TestGetStatsforBoard(t *testing.T) {
+	s, client := newStatsServer(t)
+	defer s.Close()
+
+	tests := []struct {
+		name    string
+		details []string
+	}{
+		{"counts", nil},
+		{"details", []string{"open", "older31", "notAssigned"}},
+	}
+	want := map[string]int{
+		"open":        4,
+		"new":         3,
+		"noUpdate7":   1,
+		"older7":      2,
+		"older31":     1,
+		"assigned":    2,
+		"notAssigned": 2,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := configBoards{ID: 1, Name: "Reactive", Details: tt.details}
+			stats, err := getStatsforBoard(context.Background(), client, board)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			values := stats.values()
+			for i, m := range boardMetrics {
+				if values[i] != want[m.Key] {
+					t.Errorf("%s = %d, want %d", m.Key, values[i], want[m.Key])
+				}
+			}
+
+			for _, key := range tt.details {
+				if len(stats.tickets[key]) != want[key] {
+					t.Errorf("%s listed %d tickets, want %d", key, len(stats.tickets[key]), want[key])
+				}
+			}
+			if len(stats.tickets) != len(tt.details) {
+				t.Errorf("listed tickets for %d metrics, want %d", len(stats.tickets), len(tt.details))
+			}
+		})
+	}
+}
+
+func TestGetStatsforBoardError(t *testing.T) {
+	s, client := newStatsServer(t)
+	s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := getStatsforBoard(ctx, client, configBoards{ID: 1}); err == nil {
+		t.Error("got no error from a closed server")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+
+	"github.com/simononebyte/scorecard/psa"
+)
+
+// collectSLAStats measures tickets against the targets of their SLA and
+// priority, both being in business hours:
+//
+// slaRespondedPct, the percentage of tickets first responded to in the last
+// week within the response target
+//
+// slaResolvedPct, the percentage of tickets resolved in the last week within
+// the resolution target
+//
+// slaBreaching, the open tickets currently out of SLA
+//
+// Tickets without an SLA, or whose priority has no target, are not counted.
+func collectSLAStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	stats := extraStats{}
+	targets := slaTargets{client: client, priorities: map[int][]psa.SLAPriority{}}
+
+	if board.hasExtra("slaRespondedPct") {
+		tickets, err := client.GetRespondedTicketsByBoardIDContext(ctx, board.ID, 7, psa.TicketSLAFields...)
+		if err != nil {
+			return nil, err
+		}
+		within := []bool{}
+		for _, t := range tickets {
+			target, ok, err := targets.get(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			if ok && target.RespondHours > 0 {
+				within = append(within, float64(t.RespondMinutes) <= target.RespondHours*60)
+			}
+		}
+		if pct, ok := percentTrue(within); ok {
+			stats["slaRespondedPct"] = pct
+		}
+	}
+
+	if board.hasExtra("slaResolvedPct") {
+		tickets, err := client.GetResolvedTicketsByBoardIDContext(ctx, board.ID, 7, psa.TicketSLAFields...)
+		if err != nil {
+			return nil, err
+		}
+		within := []bool{}
+		for _, t := range tickets {
+			target, ok, err := targets.get(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			if ok && target.ResolutionHours > 0 {
+				within = append(within, float64(t.ResolveMinutes) <= target.ResolutionHours*60)
+			}
+		}
+		if pct, ok := percentTrue(within); ok {
+			stats["slaResolvedPct"] = pct
+		}
+	}
+
+	if board.hasExtra("slaBreaching") {
+		n, err := client.CountOpenTicketsByBoardIDOutOfSLAContext(ctx, board.ID)
+		if err != nil {
+			return nil, err
+		}
+		stats["slaBreaching"] = float64(n)
+	}
+
+	return stats, nil
+}
+
+// slaTargets fetches the priority targets of each SLA once
+type slaTargets struct {
+	client     *psa.Client
+	priorities map[int][]psa.SLAPriority
+}
+
+// get returns the targets for the ticket's SLA and priority, false if it has
+// no SLA or the SLA has no targets for the priority
+func (s slaTargets) get(ctx context.Context, t psa.Ticket) (psa.SLAPriority, bool, error) {
+	if t.SLA.ID == 0 {
+		return psa.SLAPriority{}, false, nil
+	}
+	priorities, ok := s.priorities[t.SLA.ID]
+	if !ok {
+		var err error
+		priorities, err = s.client.GetSLAPrioritiesContext(ctx, t.SLA.ID)
+		if err != nil {
+			return psa.SLAPriority{}, false, err
+		}
+		s.priorities[t.SLA.ID] = priorities
+	}
+	for _, p := range priorities {
+		if p.Priority.ID == t.Priority.ID {
+			return p, true, nil
+		}
+	}
+	return psa.SLAPriority{}, false, nil
+}
+
+// percentTrue returns the percentage of values that are true, false if there
+// are none
+func percentTrue(values []bool) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return float64(n) / float64(len(values)) * 100, true
+}
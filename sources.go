@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+// defaultPortalSources are the ticket sources counted as the customer portal
+var defaultPortalSources = []string{"Portal"}
+
+// configSources turns on the breakdown of new tickets by source, which is
+// saved to Worksheet. Portal lists the sources counted by portalShare.
+type configSources struct {
+	Worksheet string   `json:"worksheet"`
+	Portal    []string `json:"portal"`
+}
+
+func (s configSources) enabled() bool {
+	return s.Worksheet != ""
+}
+
+func (s configSources) portal() []string {
+	if len(s.Portal) == 0 {
+		return defaultPortalSources
+	}
+	return s.Portal
+}
+
+// sourceFields are the ticket fields the source breakdown needs
+var sourceFields = []string{"id", "source/id", "source/name"}
+
+const noSource = "(none)"
+
+// sourceCount is the new tickets raised through one source
+type sourceCount struct {
+	Source string
+	New    int
+}
+
+// sourceStats holds the new tickets by source for each board, keyed by
+// board name
+type sourceStats map[string][]sourceCount
+
+// collectSourceStats counts the tickets entered in the last week on each
+// board by the source they were raised through. Every source is listed,
+// including those with no tickets. It returns nil when the breakdown is not
+// turned on.
+func collectSourceStats(ctx context.Context, c config) (sourceStats, error) {
+	if !c.Sources.enabled() {
+		return nil, nil
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := client.GetTicketSourcesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := sourceStats{}
+	for _, board := range c.Boards {
+		tickets, err := client.GetNewTicketsByBoardIDContext(ctx, board.ID, 7, sourceFields...)
+		if err != nil {
+			return nil, fmt.Errorf("board %s: %s", board.Name, err)
+		}
+		stats[board.Name] = countBySource(sources, tickets)
+	}
+	return stats, nil
+}
+
+// countBySource counts the tickets for each source in the order listed,
+// followed by any sources that are not listed, such as inactive ones
+func countBySource(sources []psa.TicketSource, tickets []psa.Ticket) []sourceCount {
+	counts := map[string]int{}
+	for _, t := range tickets {
+		name := t.Source.Name
+		if name == "" {
+			name = noSource
+		}
+		counts[name]++
+	}
+
+	list := make([]sourceCount, 0, len(sources))
+	for _, s := range sources {
+		list = append(list, sourceCount{s.Name, counts[s.Name]})
+		delete(counts, s.Name)
+	}
+	others := make([]string, 0, len(counts))
+	for name := range counts {
+		others = append(others, name)
+	}
+	sort.Strings(others)
+	for _, name := range others {
+		list = append(list, sourceCount{name, counts[name]})
+	}
+	return list
+}
+
+// collectPortalShareStats works out portalShare, the percentage of tickets
+// entered in the last week that were raised through the portal
+func collectPortalShareStats(ctx context.Context, client *psa.Client, c config, board configBoards) (extraStats, error) {
+	tickets, err := client.GetNewTicketsByBoardIDContext(ctx, board.ID, 7, sourceFields...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return extraStats{}, nil
+	}
+
+	portal := 0
+	for _, t := range tickets {
+		if containsString(c.Sources.portal(), t.Source.Name) {
+			portal++
+		}
+	}
+	return extraStats{"portalShare": float64(portal) / float64(len(tickets)) * 100}, nil
+}
+
+func printSourceStats(c config, stats sourceStats) {
+	if len(stats) == 0 {
+		return
+	}
+	for _, board := range c.Boards {
+		fmt.Printf("%s new tickets by source\n", board.Name)
+		for _, s := range stats[board.Name] {
+			fmt.Printf("  %-20s: %3d\n", s.Source, s.New)
+		}
+		fmt.Println("---------------------------")
+	}
+}
+
+var sourceHeadings = []string{"Date", "Board", "Source", "New"}
+
+// writeSourceStats adds a row per board and source to the sources
+// worksheet, replacing any rows already saved today. The worksheet is added
+// if it does not exist.
+func writeSourceStats(f *xlsx.File, c config, stats sourceStats) error {
+	if !c.Sources.enabled() {
+		return nil
+	}
+
+	sheet, err := getOrAddSheet(f, c.Sources.Worksheet, sourceHeadings)
+	if err != nil {
+		return err
+	}
+	today := clock().UTC().Truncate(24 * time.Hour)
+	if err := removeRowsForDate(sheet, today); err != nil {
+		return err
+	}
+
+	for _, board := range c.Boards {
+		for _, s := range stats[board.Name] {
+			row := sheet.AddRow()
+			row.AddCell().SetValue(today)
+			row.AddCell().SetString(board.Name)
+			row.AddCell().SetString(s.Source)
+			row.AddCell().SetInt(s.New)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+const defaultWeeklyHours = 37.5
+
+// configStaff turns on the weekly time entry stats for each member, which
+// are saved to Worksheet. Utilisation is billable hours as a percentage of
+// WeeklyHours, measured against UtilisationTarget.
+type configStaff struct {
+	Worksheet         string  `json:"worksheet"`
+	WeeklyHours       float64 `json:"weekly_hours"`
+	UtilisationTarget float64 `json:"utilisation_target"`
+
+	// Members lists the member identifiers to include, all active members
+	// when empty
+	Members []string `json:"members"`
+
+	// ProjectBoards lists the boards, by name, whose service tickets count
+	// as project work along with project tickets. Time on the psa_boards
+	// counts as reactive work.
+	ProjectBoards []string `json:"project_boards"`
+}
+
+func (s configStaff) enabled() bool {
+	return s.Worksheet != ""
+}
+
+func (s configStaff) weeklyHours() float64 {
+	if s.WeeklyHours <= 0 {
+		return defaultWeeklyHours
+	}
+	return s.WeeklyHours
+}
+
+func (s configStaff) includes(m psa.Member) bool {
+	if len(s.Members) == 0 {
+		return true
+	}
+	for _, id := range s.Members {
+		if strings.EqualFold(id, m.Identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// staffStat is the time one member logged in the last week
+type staffStat struct {
+	Member      string
+	Hours       float64
+	Billable    float64
+	Utilisation float64
+	Reactive    float64
+	Project     float64
+
+	// Scheduled is the hours booked in the last week, to compare with Hours,
+	// and ScheduledNext the hours booked in the coming week
+	Scheduled     float64
+	ScheduledNext float64
+}
+
+// staffStats is sorted by member name
+type staffStats []staffStat
+
+// ticketBoardFields are the ticket fields needed to find a ticket's board
+var ticketBoardFields = []string{"id", "board/id", "board/name"}
+
+// collectStaffStats totals the time entries started in the last week for
+// each member. It returns nil when the stats are not turned on.
+func collectStaffStats(ctx context.Context, c config) (staffStats, error) {
+	if !c.Staff.enabled() {
+		return nil, nil
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+	members, err := client.GetMembersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	to := clock()
+	entries, err := client.GetTimeEntriesContext(ctx, to.AddDate(0, 0, -7), to, psa.TimeEntryFields...)
+	if err != nil {
+		return nil, err
+	}
+	boards, err := ticketBoards(ctx, client, entries)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := client.GetScheduleEntriesContext(ctx, to.AddDate(0, 0, -7), to.AddDate(0, 0, 7), psa.ScheduleEntryFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	byMember := map[int]*staffStat{}
+	for _, m := range members {
+		if c.Staff.includes(m) {
+			byMember[m.ID] = &staffStat{Member: m.Name}
+		}
+	}
+	for _, e := range entries {
+		s, ok := byMember[e.Member.ID]
+		if !ok {
+			continue
+		}
+		s.Hours += e.ActualHours
+		if e.IsBillable() {
+			s.Billable += e.ActualHours
+		}
+		switch {
+		case e.ChargeToType == psa.ChargeToProjectTicket:
+			s.Project += e.ActualHours
+		case e.ChargeToType != psa.ChargeToServiceTicket:
+			// time on activities and charge codes is neither
+		case containsString(c.Staff.ProjectBoards, boards[e.ChargeToID].Name):
+			s.Project += e.ActualHours
+		case isReactiveBoard(c, boards[e.ChargeToID]):
+			s.Reactive += e.ActualHours
+		}
+	}
+
+	for _, e := range schedule {
+		s, ok := byMember[e.Member.ID]
+		if !ok {
+			continue
+		}
+		if e.DateStart.Before(to) {
+			s.Scheduled += e.Hours
+		} else {
+			s.ScheduledNext += e.Hours
+		}
+	}
+
+	stats := make(staffStats, 0, len(byMember))
+	for _, s := range byMember {
+		s.Utilisation = s.Billable / c.Staff.weeklyHours() * 100
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Member < stats[j].Member })
+	return stats, nil
+}
+
+// ticketBoards returns the board of each service ticket time was logged
+// against, keyed by ticket ID
+func ticketBoards(ctx context.Context, client *psa.Client, entries []psa.TimeEntry) (map[int]psa.Board, error) {
+	ids := []int{}
+	seen := map[int]bool{}
+	for _, e := range entries {
+		if e.ChargeToType == psa.ChargeToServiceTicket && !seen[e.ChargeToID] {
+			seen[e.ChargeToID] = true
+			ids = append(ids, e.ChargeToID)
+		}
+	}
+	tickets, err := client.GetTicketsByIDContext(ctx, ids, ticketBoardFields...)
+	if err != nil {
+		return nil, err
+	}
+	boards := map[int]psa.Board{}
+	for _, t := range tickets {
+		boards[t.ID] = t.Board
+	}
+	return boards, nil
+}
+
+// isReactiveBoard reports whether the board is one of the psa_boards
+func isReactiveBoard(c config, board psa.Board) bool {
+	for _, b := range c.Boards {
+		if b.ID == board.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func printStaffStats(c config, stats staffStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Println("Staff time in the last 7 days")
+	for _, s := range stats {
+		target := ""
+		if c.Staff.UtilisationTarget > 0 && s.Utilisation < c.Staff.UtilisationTarget {
+			target = " (below target)"
+		}
+		fmt.Printf("  %-25.25s: %5s hours %5s billable %5s%% utilised%s, %s reactive %s project\n",
+			s.Member, formatStat(s.Hours), formatStat(s.Billable), formatStat(s.Utilisation), target,
+			formatStat(s.Reactive), formatStat(s.Project))
+		fmt.Printf("  %-25.25s  %5s scheduled, %s scheduled for the next 7 days\n",
+			"", formatStat(s.Scheduled), formatStat(s.ScheduledNext))
+	}
+	fmt.Println("---------------------------")
+}
+
+var staffHeadings = []string{"Date", "Member", "Hours", "Billable Hours", "Utilisation %",
+	"Utilisation Target %", "Reactive Hours", "Project Hours", "Scheduled Hours", "Scheduled Hours Next 7 Days"}
+
+// writeStaffStats adds a row per member to the staff worksheet, replacing
+// any rows already saved today. The worksheet is added if it does not exist.
+func writeStaffStats(f *xlsx.File, c config, stats staffStats) error {
+	if !c.Staff.enabled() {
+		return nil
+	}
+
+	sheet, err := getOrAddSheet(f, c.Staff.Worksheet, staffHeadings)
+	if err != nil {
+		return err
+	}
+	addMissingHeadings(sheet, staffHeadings)
+	today := clock().UTC().Truncate(24 * time.Hour)
+	if err := removeRowsForDate(sheet, today); err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		row := sheet.AddRow()
+		row.AddCell().SetValue(today)
+		row.AddCell().SetString(s.Member)
+		row.AddCell().SetFloat(roundStat(s.Hours))
+		row.AddCell().SetFloat(roundStat(s.Billable))
+		row.AddCell().SetFloat(roundStat(s.Utilisation))
+		if c.Staff.UtilisationTarget > 0 {
+			row.AddCell().SetFloat(c.Staff.UtilisationTarget)
+		} else {
+			row.AddCell()
+		}
+		row.AddCell().SetFloat(roundStat(s.Reactive))
+		row.AddCell().SetFloat(roundStat(s.Project))
+		row.AddCell().SetFloat(roundStat(s.Scheduled))
+		row.AddCell().SetFloat(roundStat(s.ScheduledNext))
+	}
+	return nil
+}
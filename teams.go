@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simononebyte/scorecard/psa"
+	"github.com/tealeg/xlsx"
+)
+
+// configTeam saves stats for a ConnectWise service team to its own
+// worksheet, as an alternative to grouping tickets by board. The team is
+// found by ID, or by name when no ID is given, in which case the name is
+// only needed to label the team.
+type configTeam struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Worksheet string `json:"worksheet"`
+}
+
+func validateTeams(teams []configTeam) error {
+	for _, t := range teams {
+		if (t.ID == 0 && t.Name == "") || t.Worksheet == "" {
+			return fmt.Errorf("each of psa_teams needs an id or name, and a worksheet")
+		}
+	}
+	return nil
+}
+
+// key identifies the team in teamStatsMap, by its ID when it has one
+func (t configTeam) key() string {
+	if t.ID != 0 {
+		return strconv.Itoa(t.ID)
+	}
+	return strings.ToLower(t.Name)
+}
+
+// label names the team in errors, before its PSA name is known
+func (t configTeam) label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("#%d", t.ID)
+}
+
+// teamMetrics are the stats collected for each team, in worksheet column
+// order
+var teamMetrics = []boardMetric{
+	{"open", "Open"},
+	{"new", "New"},
+	{"older7", "Older 7 days"},
+	{"notAssigned", "Not Assigned"},
+}
+
+// teamQueries count the tickets behind each teamMetrics key
+var teamQueries = map[string]func(ctx context.Context, client *psa.Client, teamID int) (int, error){
+	"open": func(ctx context.Context, client *psa.Client, teamID int) (int, error) {
+		return client.CountOpenTicketsByTeamIDContext(ctx, teamID)
+	},
+	"new": func(ctx context.Context, client *psa.Client, teamID int) (int, error) {
+		return client.CountNewTicketsByTeamIDContext(ctx, teamID, 7)
+	},
+	"older7": func(ctx context.Context, client *psa.Client, teamID int) (int, error) {
+		return client.CountOpenTicketsByTeamIDOlderThanContext(ctx, teamID, 7)
+	},
+	"notAssigned": func(ctx context.Context, client *psa.Client, teamID int) (int, error) {
+		return client.CountOpenNotAssignedTicketsByTeamIDContext(ctx, teamID)
+	},
+}
+
+// teamStat holds a team's name, its stats in teamMetrics order and the names
+// of its members. The name is the configured one, or the team's name in the
+// PSA when only an id is configured.
+type teamStat struct {
+	name    string
+	values  []int
+	members []string
+}
+
+// teamStatsMap is keyed by configTeam.key
+type teamStatsMap map[string]teamStat
+
+// collectTeamStats gets the stats for every configured team
+func collectTeamStats(ctx context.Context, c config) (teamStatsMap, error) {
+	if len(c.Teams) == 0 {
+		return nil, nil
+	}
+
+	client, err := psa.NewClientContext(ctx, c.ConnectWise, excludeBoards)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := client.GetTeamsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := client.GetMembersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := teamStatsMap{}
+	for _, ct := range c.Teams {
+		team, ok := findTeam(teams, ct)
+		if !ok {
+			return nil, fmt.Errorf("team %s not found", ct.label())
+		}
+
+		stat := teamStat{name: ct.Name}
+		if stat.name == "" {
+			stat.name = team.Name
+		}
+		for _, m := range teamMetrics {
+			n, err := teamQueries[m.Key](ctx, client, team.ID)
+			if err != nil {
+				return nil, fmt.Errorf("team %s: %s", stat.name, err)
+			}
+			stat.values = append(stat.values, n)
+		}
+		for _, m := range members {
+			if team.HasMember(m.ID) {
+				stat.members = append(stat.members, m.Name)
+			}
+		}
+		stats[ct.key()] = stat
+	}
+	return stats, nil
+}
+
+func findTeam(teams []psa.Team, ct configTeam) (psa.Team, bool) {
+	for _, t := range teams {
+		if ct.ID != 0 && t.ID == ct.ID {
+			return t, true
+		}
+		if ct.ID == 0 && strings.EqualFold(t.Name, ct.Name) {
+			return t, true
+		}
+	}
+	return psa.Team{}, false
+}
+
+func printTeamStats(c config, stats teamStatsMap) {
+	for _, ct := range c.Teams {
+		stat, ok := stats[ct.key()]
+		if !ok {
+			continue
+		}
+		fmt.Printf("Team %s (%s)\n", stat.name, strings.Join(stat.members, ", "))
+		for i, m := range teamMetrics {
+			fmt.Printf("  %-20s: %3d\n", m.Label, stat.values[i])
+		}
+		fmt.Println("---------------------------")
+	}
+}
+
+// writeTeamStats adds a row of this week's stats to each team's worksheet,
+// replacing any row from an earlier run today. A missing worksheet is added.
+func writeTeamStats(f *xlsx.File, c config, stats teamStatsMap) error {
+	headings := []string{"Date"}
+	for _, m := range teamMetrics {
+		headings = append(headings, m.Label)
+	}
+
+	for _, ct := range c.Teams {
+		stat, ok := stats[ct.key()]
+		if !ok {
+			continue
+		}
+		sheet, err := getOrAddSheet(f, ct.Worksheet, headings)
+		if err != nil {
+			return err
+		}
+
+		today := clock().UTC().Truncate(24 * time.Hour)
+		if err := removeRowsForDate(sheet, today); err != nil {
+			return err
+		}
+		row := sheet.AddRow()
+		row.AddCell().SetValue(today)
+		for _, v := range stat.values {
+			row.AddCell().SetValue(v)
+		}
+	}
+	return nil
+}
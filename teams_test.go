@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestValidateTeams(t *testing.T) {
+	tests := []struct {
+		team    configTeam
+		wantErr bool
+	}{
+		{configTeam{Name: "Helpdesk", Worksheet: "Helpdesk"}, false},
+		{configTeam{ID: 5, Worksheet: "Helpdesk"}, false},
+		{configTeam{ID: 5, Name: "Helpdesk", Worksheet: "Helpdesk"}, false},
+		{configTeam{Worksheet: "Helpdesk"}, true},
+		{configTeam{ID: 5, Name: "Helpdesk"}, true},
+	}
+	for _, tt := range tests {
+		if err := validateTeams([]configTeam{tt.team}); (err != nil) != tt.wantErr {
+			t.Errorf("%+v: got error %v, want error %v", tt.team, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWriteTeamStats(t *testing.T) {
+	defer func(c func() time.Time) { clock = c }(clock)
+	clock = func() time.Time { return testNow }
+
+	c := config{Teams: []configTeam{
+		{ID: 5, Worksheet: "Helpdesk"},
+		{Name: "Projects", Worksheet: "Projects"},
+	}}
+	stats := teamStatsMap{
+		"5":        {name: "Helpdesk", values: []int{4, 3, 2, 1}},
+		"projects": {name: "Projects", values: []int{8, 7, 6, 5}},
+	}
+
+	f := xlsx.NewFile()
+	// An existing but empty worksheet gets its headings
+	if _, err := f.AddSheet("Helpdesk"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run on the same day replaces the first run's row
+	for run := 0; run < 2; run++ {
+		if err := writeTeamStats(f, c, stats); err != nil {
+			t.Fatal(err)
+		}
+		stats["5"].values[0]++
+	}
+
+	for name, want := range map[string]int{"Helpdesk": 5, "Projects": 8} {
+		sheet := getSheet(f, name)
+		if sheet == nil || len(sheet.Rows) != 2 {
+			t.Fatalf("%s: want a heading row and one row of stats", name)
+		}
+		if h := sheet.Rows[0].Cells[1].String(); h != "Open" {
+			t.Errorf("%s: heading %q, want Open", name, h)
+		}
+		if v, _ := sheet.Rows[1].Cells[1].Int(); v != want {
+			t.Errorf("%s: open = %d, want %d", name, v, want)
+		}
+	}
+}
+
+func TestIsLastRowTodayEmptySheet(t *testing.T) {
+	f := xlsx.NewFile()
+	sheet, _ := f.AddSheet("Empty")
+	if isLastRowToday(sheet) {
+		t.Error("an empty worksheet has a row for today")
+	}
+	sheet.AddRow()
+	if isLastRowToday(sheet) {
+		t.Error("a row with no cells is a row for today")
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+const (
+	defaultStatsBackups = 5
+	backupStampFormat   = "20060102-150405.000"
+)
+
+// statsBackups is how many workbook backups to keep. Leaving stats_backups
+// out keeps the default, 0 or less turns backups off.
+func (c config) statsBackups() int {
+	if c.StatsBackups == nil {
+		return defaultStatsBackups
+	}
+	return *c.StatsBackups
+}
+
+// saveWorkbook writes the workbook to path without ever leaving a half
+// written file behind. The workbook is written to a temp file in the same
+// directory and renamed over the target once complete. The previous version
+// is kept as a timestamped backup, with at most backups copies retained, or
+// none when backups is 0 or less. Old backups are only removed once the
+// workbook has been replaced.
+//
+// If the target is open in Excel (a ~$ lock file exists, or the rename is
+// refused) the workbook is written to a side file next to the target and an
+// error naming that file is returned.
+func saveWorkbook(f *xlsx.File, path string, backups int) error {
+	if isWorkbookLocked(path) {
+		return saveSideFile(f, path, fmt.Errorf("%s is open in Excel", path))
+	}
+
+	tmp, err := writeTempWorkbook(f, path)
+	if err != nil {
+		return err
+	}
+
+	backup := ""
+	if backups > 0 {
+		if backup, err = backupWorkbook(path); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		if backup != "" {
+			// The workbook is unchanged, so its backup is not needed
+			os.Remove(backup)
+		}
+		return saveSideFile(f, path, err)
+	}
+
+	if backups > 0 {
+		return rotateBackups(path, backups)
+	}
+	return nil
+}
+
+// isWorkbookLocked reports whether Excel's owner file (~$Name.xlsx) exists
+// alongside the workbook
+func isWorkbookLocked(path string) bool {
+	dir, name := filepath.Split(path)
+	_, err := os.Stat(filepath.Join(dir, "~$"+name))
+	return err == nil
+}
+
+// writeTempWorkbook writes f to a temp file beside path and returns its name
+func writeTempWorkbook(f *xlsx.File, path string) (string, error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("error creating temp workbook: %s", err)
+	}
+
+	if err := f.Write(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing temp workbook: %s", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing temp workbook: %s", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing temp workbook: %s", err)
+	}
+	return tmp.Name(), nil
+}
+
+// backupWorkbook copies the current workbook to a timestamped backup,
+// returning its name, or "" when there is no workbook yet
+func backupWorkbook(path string) (string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading workbook for backup: %s", err)
+	}
+
+	backup, err := stampedName(path, "bak")
+	if err != nil {
+		return "", fmt.Errorf("error naming workbook backup: %s", err)
+	}
+	if err := ioutil.WriteFile(backup, data, 0644); err != nil {
+		return "", fmt.Errorf("error writing workbook backup: %s", err)
+	}
+	return backup, nil
+}
+
+// rotateBackups removes the oldest backups so that no more than keep remain
+func rotateBackups(path string, keep int) error {
+	ext := filepath.Ext(path)
+	pattern := strings.TrimSuffix(path, ext) + ".*.bak" + ext
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+
+	// The timestamp sorts lexically, so the oldest backups come first
+	sort.Strings(backups)
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("error removing old backup: %s", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// saveSideFile is the fallback when the workbook cannot be replaced
+func saveSideFile(f *xlsx.File, path string, cause error) error {
+	side, err := stampedName(path, "unsaved")
+	if err != nil {
+		return fmt.Errorf("unable to save %s (%s) and unable to name side file: %s", path, cause, err)
+	}
+	if err := f.Save(side); err != nil {
+		return fmt.Errorf("unable to save %s (%s) and unable to write side file %s: %s",
+			path, cause, side, err)
+	}
+	return fmt.Errorf("unable to save %s (%s)\nstats have been written to %s instead, "+
+		"copy them into the workbook once it is closed", path, cause, side)
+}
+
+// stampedName returns path with a timestamp and tag inserted before the
+// extension, e.g. Scorecard.20191028-100000.123.bak.xlsx. The timestamp is
+// moved on a millisecond at a time until the name is unused, so names stay
+// unique and in order however quickly they are made. An error checking
+// whether a name is in use is returned.
+func stampedName(path, tag string) (string, error) {
+	ext := filepath.Ext(path)
+	t := time.Now()
+	for {
+		name := fmt.Sprintf("%s.%s.%s%s", strings.TrimSuffix(path, ext), t.Format(backupStampFormat), tag, ext)
+		_, err := os.Stat(name)
+		if os.IsNotExist(err) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// getOrAddSheet returns the named worksheet, adding it with a row of
+// headings if it does not exist. Headings are also added to an existing
+// worksheet that is empty.
+func getOrAddSheet(f *xlsx.File, name string, headings []string) (*xlsx.Sheet, error) {
+	sheet := getSheet(f, name)
+	if sheet != nil && len(sheet.Rows) > 0 {
+		return sheet, nil
+	}
+	if sheet == nil {
+		var err error
+		if sheet, err = f.AddSheet(name); err != nil {
+			return nil, err
+		}
+	}
+	row := sheet.AddRow()
+	for _, h := range headings {
+		row.AddCell().SetString(h)
+	}
+	return sheet, nil
+}
+
+// addMissingHeadings labels the columns added to a worksheet since it was
+// created, leaving any headings already there alone
+func addMissingHeadings(sheet *xlsx.Sheet, headings []string) {
+	if len(sheet.Rows) == 0 || !isHeaderRow(sheet.Rows[0]) {
+		return
+	}
+	header := sheet.Rows[0]
+	if len(header.Cells) >= len(headings) {
+		return
+	}
+	for _, h := range headings[len(header.Cells):] {
+		header.AddCell().SetString(h)
+	}
+}
+
+// removeRowsForDate removes the rows whose first cell is date, so a second
+// run on the same day replaces the rows from the first
+func removeRowsForDate(sheet *xlsx.Sheet, date time.Time) error {
+	for i := len(sheet.Rows) - 1; i >= 0; i-- {
+		row := sheet.Rows[i]
+		if row == nil || len(row.Cells) == 0 {
+			continue
+		}
+		if d, err := row.Cells[0].GetTime(false); err == nil && d.Equal(date) {
+			if err := sheet.RemoveRowAtIndex(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestStatsBackupsConfig(t *testing.T) {
+	tests := []struct {
+		json string
+		want int
+	}{
+		{`{}`, defaultStatsBackups},
+		{`{"stats_backups": 0}`, 0},
+		{`{"stats_backups": 2}`, 2},
+		{`{"stats_backups": -1}`, -1},
+	}
+	for _, tt := range tests {
+		var c config
+		if err := json.Unmarshal([]byte(tt.json), &c); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.statsBackups(); got != tt.want {
+			t.Errorf("%s kept %d backups, want %d", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestSaveWorkbookBackups(t *testing.T) {
+	tests := []struct {
+		backups int
+		saves   int
+		want    int
+	}{
+		{backups: 3, saves: 6, want: 3},
+		{backups: 5, saves: 2, want: 1},
+		{backups: 0, saves: 3, want: 0},
+		{backups: -1, saves: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		dir, err := ioutil.TempDir("", "scorecard")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "Scorecard.xlsx")
+		// Saved in quick succession, so backups share the same second
+		for i := 0; i < tt.saves; i++ {
+			f := xlsx.NewFile()
+			sheet, _ := f.AddSheet("Reactive")
+			sheet.AddRow().AddCell().SetInt(i)
+			if err := saveWorkbook(f, path, tt.backups); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		backups, _ := filepath.Glob(filepath.Join(dir, "Scorecard.*.bak.xlsx"))
+		if len(backups) != tt.want {
+			t.Errorf("keeping %d: %d saves left %d backups, want %d", tt.backups, tt.saves, len(backups), tt.want)
+			continue
+		}
+		if len(backups) == 0 {
+			continue
+		}
+
+		// The newest backups are kept, the last holding the second to last save
+		sort.Strings(backups)
+		f, err := xlsx.OpenFile(backups[len(backups)-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := f.Sheets[0].Rows[0].Cells[0].Int(); v != tt.saves-2 {
+			t.Errorf("keeping %d: newest backup holds save %d, want %d", tt.backups, v, tt.saves-2)
+		}
+	}
+}
+
+func TestStampedNameStatError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scorecard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A file where the directory should be fails Stat with something other
+	// than not exist, which must not loop forever
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stampedName(filepath.Join(file, "Scorecard.xlsx"), "bak"); err == nil {
+		t.Error("got no error when the name could not be checked")
+	}
+
+	name, err := stampedName(filepath.Join(dir, "Scorecard.xlsx"), "bak")
+	if err != nil || filepath.Dir(name) != dir {
+		t.Errorf("got %q %v, want a backup name in %s", name, err, dir)
+	}
+}